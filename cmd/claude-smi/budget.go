@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anomredux/claude-smi/internal/config"
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+// runBudget implements "claude-smi budget status": it prints the progress
+// of every configured budget through its current period and returns the
+// process exit code, which is 1 when a budget is exceeded.
+func runBudget(args []string) int {
+	if len(args) == 0 || args[0] != "status" {
+		fmt.Fprintln(os.Stderr, "usage: claude-smi budget status [flags]")
+		return 2
+	}
+	fs := flag.NewFlagSet("budget status", flag.ContinueOnError)
+	var (
+		configPath = fs.String("config", config.DefaultPath(), "config file path")
+		dataDir    = fs.String("data-dir", "", "Claude data directories, comma-separated (default: $CLAUDE_CONFIG_DIR or auto-discover)")
+		timezone   = fs.String("timezone", "", "override timezone (e.g., Asia/Seoul)")
+		jsonOut    = fs.Bool("json", false, "output the budgets as JSON")
+		noCache    = fs.Bool("no-cache", false, "parse all logs from scratch without the on-disk index")
+		noArchive  = fs.Bool("no-archive", false, "neither read nor extend the usage archive")
+	)
+	if err := fs.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+	if *timezone != "" {
+		cfg.General.Timezone = *timezone
+	}
+	tz, err := time.LoadLocation(cfg.General.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %s\n", cfg.General.Timezone)
+		return 2
+	}
+	budgets, err := configBudgets(cfg.Budgets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid [budgets]: %v\n", err)
+		return 2
+	}
+	if len(budgets) == 0 {
+		fmt.Fprintf(os.Stderr, "No budgets configured; add a [budgets] section to %s\n", *configPath)
+		return 2
+	}
+
+	acc := domain.NewBudgetAccumulator(budgets, domain.BudgetOptions{
+		Now:        time.Now(),
+		Location:   tz,
+		BillingDay: cfg.Plan.Day(),
+	})
+	statuses := acc.Statuses()
+	// Only the current periods matter
+	since := statuses[0].Start
+	for _, s := range statuses[1:] {
+		if s.Start.Before(since) {
+			since = s.Start
+		}
+	}
+	opts := sourceOptions(cfg, *dataDir, *noCache, *noArchive)
+	opts.window = domain.TimeRange{Since: since}
+	streamEntries(cfg, opts, tz, acc.Add, nil)
+	statuses = acc.Statuses()
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(statuses); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			return 2
+		}
+	} else {
+		writeBudgets(os.Stdout, statuses)
+	}
+	for _, s := range statuses {
+		if s.Share >= 1 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// configBudgets converts the [budgets] config.
+func configBudgets(c config.BudgetsConfig) ([]domain.Budget, error) {
+	var budgets []domain.Budget
+	for _, l := range c.All() {
+		period, err := domain.ParseBudgetPeriod(l.Period)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, domain.Budget{Period: period, Cost: l.Cost, Tokens: l.Tokens, Project: l.Project, Model: l.Model})
+	}
+	return budgets, nil
+}
+
+// writeBudgets prints one row per budget with its period, the cost and
+// tokens against their limits and the share used.
+func writeBudgets(w io.Writer, statuses []domain.BudgetStatus) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BUDGET\tPERIOD\tCOST\tTOKENS\tUSED")
+	for _, s := range statuses {
+		cost, tokens := "-", "-"
+		if s.Cost > 0 {
+			cost = fmt.Sprintf("$%.2f / $%.2f", s.Spent, s.Cost)
+		}
+		if s.Tokens > 0 {
+			tokens = fmt.Sprintf("%d / %d", s.Used, s.Tokens)
+		}
+		period := s.Start.Format("2006-01-02") + " – " + s.End.AddDate(0, 0, -1).Format("2006-01-02")
+		used := fmt.Sprintf("%.0f%%", s.Share*100)
+		if s.Share >= 1 {
+			used += " exceeded"
+		}
+		fmt.Fprintln(tw, strings.Join([]string{s.Name(), period, cost, tokens, used}, "\t"))
+	}
+	tw.Flush()
+}
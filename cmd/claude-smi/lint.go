@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anomredux/claude-smi/internal/config"
+	"github.com/anomredux/claude-smi/internal/lint"
+	"github.com/anomredux/claude-smi/internal/pricing"
+)
+
+// runLintLogs implements "claude-smi lint-logs": it checks every log for
+// records the parser cannot use and returns the process exit code, which
+// is 1 when the overall error rate exceeds --max-error-rate.
+func runLintLogs(args []string) int {
+	fs := flag.NewFlagSet("lint-logs", flag.ContinueOnError)
+	var (
+		configPath   = fs.String("config", config.DefaultPath(), "config file path")
+		dataDir      = fs.String("data-dir", "", "Claude data directories, comma-separated (default: $CLAUDE_CONFIG_DIR or auto-discover)")
+		jsonOut      = fs.Bool("json", false, "output the report as JSON")
+		all          = fs.Bool("all", false, "list every file, not only files with problems")
+		offline      = fs.Bool("offline", false, "check prices against the embedded table only")
+		maxErrorRate = fs.Float64("max-error-rate", 0.01, "fail when the share of unusable lines exceeds this (0-1)")
+	)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+
+	prices, err := pricing.LoadDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading pricing: %v\n", err)
+		return 2
+	}
+	if !*offline {
+		if fetched, fetchErr := pricing.FetchLiteLLM(context.Background()); fetchErr == nil {
+			prices.Merge(fetched)
+		}
+	}
+
+	dirs := config.ClaudeDataDirs(config.SplitList(*dataDir), cfg.General.DataDirs)
+	report := lint.Check(context.Background(), dirs, lint.Options{
+		Workers: cfg.General.ParseWorkers,
+		Prices:  prices,
+	})
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			return 2
+		}
+	} else {
+		writeLintReport(os.Stdout, report, *all)
+	}
+
+	if report.Total.ErrorRate > *maxErrorRate {
+		fmt.Fprintf(os.Stderr, "Error rate %.2f%% exceeds %.2f%%\n",
+			report.Total.ErrorRate*100, *maxErrorRate*100)
+		return 1
+	}
+	return 0
+}
+
+// writeLintReport prints a table of files (only those with problems unless
+// all is set) followed by the totals and any unknown types or models.
+func writeLintReport(w io.Writer, report lint.Report, all bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "LINES\tENTRIES\tMALFORMED\tBAD TS\tNO USAGE\tDUP %\tERR %\t FILE")
+	row := func(r lint.FileReport, name string) {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%.1f\t%.2f\t %s\n",
+			r.Lines, r.Entries, r.Malformed, r.BadTimestamps, r.MissingUsage,
+			r.DuplicateRatio*100, r.ErrorRate*100, name)
+	}
+	for _, f := range report.Files {
+		if all || f.Problems() {
+			row(f, f.Path)
+		}
+	}
+	row(report.Total, fmt.Sprintf("total (%d files)", len(report.Files)))
+	tw.Flush()
+
+	if len(report.Total.UnknownTypes)+len(report.Total.UnpricedModels) > 0 {
+		fmt.Fprintln(w)
+	}
+	if len(report.Total.UnknownTypes) > 0 {
+		fmt.Fprintf(w, "Unknown record types: %s\n", formatCounts(report.Total.UnknownTypes))
+	}
+	if len(report.Total.UnpricedModels) > 0 {
+		fmt.Fprintf(w, "Models without a price: %s\n", formatCounts(report.Total.UnpricedModels))
+	}
+}
+
+// formatCounts renders counts as "a (3), b (1)", most frequent first.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		name := k
+		if name == "" {
+			name = `""`
+		}
+		parts[i] = fmt.Sprintf("%s (%d)", name, counts[k])
+	}
+	return strings.Join(parts, ", ")
+}
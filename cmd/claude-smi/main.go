@@ -7,31 +7,50 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/anomredux/claude-smi/internal/archive"
 	"github.com/anomredux/claude-smi/internal/config"
 	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/history"
 	"github.com/anomredux/claude-smi/internal/parser"
 	"github.com/anomredux/claude-smi/internal/pricing"
+	"github.com/anomredux/claude-smi/internal/project"
 	"github.com/anomredux/claude-smi/internal/ui"
 )
 
 // version is set by goreleaser via ldflags.
 var version = "dev"
 
-// maxEntries limits the number of entries to prevent OOM on huge histories.
-const maxEntries = 500_000
-
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "lint-logs":
+			os.Exit(runLintLogs(os.Args[2:]))
+		case "query":
+			os.Exit(runQuery(os.Args[2:]))
+		case "budget":
+			os.Exit(runBudget(os.Args[2:]))
+		}
+	}
+
 	var (
 		configPath  = flag.String("config", config.DefaultPath(), "config file path")
-		dataDir     = flag.String("data-dir", defaultDataDir(), "Claude Code data directory")
+		dataDir     = flag.String("data-dir", "", "Claude data directories, comma-separated (default: $CLAUDE_CONFIG_DIR or auto-discover)")
 		noTUI       = flag.Bool("no-tui", false, "output JSON to stdout instead of TUI")
-		view        = flag.String("view", "daily", "view for --no-tui: daily, blocks")
+		view        = flag.String("view", "daily", "view for --no-tui: daily, blocks, weekly, sessions, projects, heatmap, forecast, anomalies, value, cache, branches, versions, tools, prompts")
 		timezone    = flag.String("timezone", "", "override timezone (e.g., Asia/Seoul)")
-		since       = flag.String("since", "", "filter entries from this date (YYYY-MM-DD)")
-		until       = flag.String("until", "", "filter entries until this date (YYYY-MM-DD)")
+		since       = flag.String("since", "", rangeFlagHelp("start"))
+		until       = flag.String("until", "", rangeFlagHelp("end"))
+		filterExpr  = flag.String("filter", "", `filter expression, e.g. "model~opus and cost>0.5"`)
+		branch      = flag.String("branch", "", "only include entries recorded on this git branch")
+		cliVersion  = flag.String("cli-version", "", "only include entries from this Claude Code version")
+		sidechain   = flag.String("sidechain", "include", "subagent traffic: include, only, exclude")
+		noCache     = flag.Bool("no-cache", false, "parse all logs from scratch without the on-disk index")
+		noArchive   = flag.Bool("no-archive", false, "neither read nor extend the usage archive")
 		showVersion = flag.Bool("version", false, "print version and exit")
 	)
 	flag.Parse()
@@ -57,24 +76,52 @@ func main() {
 	}
 
 	// Validate date filters
-	for _, df := range []struct{ name, val string }{{"--since", *since}, {"--until", *until}} {
-		if df.val != "" {
-			if _, err := time.Parse("2006-01-02", df.val); err != nil {
-				fmt.Fprintf(os.Stderr, "Invalid %s date (use YYYY-MM-DD): %s\n", df.name, df.val)
-				os.Exit(1)
-			}
-		}
+	if _, err := rangeContext(cfg).Parse(*since, *until); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --since/--until: %v\n", err)
+		os.Exit(1)
+	}
+
+	meta := domain.MetadataFilter{Branch: *branch, Version: *cliVersion}
+	switch *sidechain {
+	case "include":
+	case "only":
+		meta.Sidechain = domain.SidechainOnly
+	case "exclude":
+		meta.Sidechain = domain.SidechainExclude
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid --sidechain (use include, only or exclude): %s\n", *sidechain)
+		os.Exit(1)
+	}
+
+	opts := sourceOptions(cfg, *dataDir, *noCache, *noArchive)
+
+	windows := history.New(filepath.Join(config.DataDir(), "windows.json"))
+	if err := windows.Load(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read observed windows: %v\n", err)
 	}
 
 	if *noTUI {
-		runNoTUI(cfg, *dataDir, *view, *since, *until)
+		opts.windows = windows
+		opts.view = *view
+		opts.since, opts.until = *since, *until
+		opts.meta = meta
+		opts.filter = *filterExpr
+		runNoTUI(cfg, opts)
 		return
 	}
 
 	app := ui.NewApp(cfg)
-	app.DataDir = *dataDir
+	app.DataDirs = opts.dataDirs
+	app.IndexPath = opts.indexPath
+	app.Archive = opts.archive
+	app.Windows = windows
 	app.SinceFilter = *since
 	app.UntilFilter = *until
+	app.MetaFilter = meta
+	if err := app.SetFilter(*filterExpr); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --filter: %v\n", err)
+		os.Exit(1)
+	}
 	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
@@ -83,63 +130,440 @@ func main() {
 	}
 }
 
-func runNoTUI(cfg config.Config, dataDir, view, since, until string) {
-	// Load timezone
+// noTUIOptions carries the settings of the --no-tui pipeline.
+type noTUIOptions struct {
+	dataDirs  []string
+	indexPath string
+	archive   *archive.Store // nil disables the archive
+	windows   *history.Store // observed API windows that anchor blocks
+	view      string
+	since     string
+	until     string
+	window    domain.TimeRange // further restricts entries; never echoed
+	meta      domain.MetadataFilter
+	filter    string              // filter expression
+	calc      *pricing.Calculator // nil loads the prices in streamEntries
+}
+
+// rangeContext resolves --since and --until now, in the configured
+// timezone and billing cycle.
+func rangeContext(cfg config.Config) domain.RangeContext {
 	tz, err := time.LoadLocation(cfg.General.Timezone)
 	if err != nil {
 		tz = time.UTC
 	}
+	return domain.RangeContext{Now: time.Now(), Location: tz, BillingDay: cfg.Plan.Day()}
+}
 
-	// Scan and parse all JSONL files
-	entries := parser.ScanAndParse(context.Background(), dataDir)
-	if len(entries) > maxEntries {
-		entries = entries[len(entries)-maxEntries:]
-	}
-
-	// Dedup
-	entries = parser.Dedup(entries)
+// rangeFlagHelp returns the usage of --since or --until.
+func rangeFlagHelp(bound string) string {
+	return bound + " of the date range: YYYY-MM-DD, RFC 3339, now, 7d, 24h, 2w, " +
+		strings.Join(domain.RangePeriods, ", ")
+}
 
-	// Apply pricing: start with embedded defaults, overlay with LiteLLM
-	table, err := pricing.LoadDefault()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error loading pricing: %v\n", err)
-		os.Exit(1)
+// sourceOptions returns options locating the logs, the parse index and
+// the archive, from the --data-dir, --no-cache and --no-archive flags.
+func sourceOptions(cfg config.Config, dataDir string, noCache, noArchive bool) noTUIOptions {
+	opts := noTUIOptions{
+		dataDirs:  config.ClaudeDataDirs(config.SplitList(dataDir), cfg.General.DataDirs),
+		indexPath: filepath.Join(config.CacheDir(), "index.gob"),
 	}
-	if fetched, fetchErr := pricing.FetchLiteLLM(context.Background()); fetchErr == nil {
-		table.Merge(fetched)
+	if noCache {
+		opts.indexPath = ""
 	}
-	calc := pricing.NewCalculator(table, pricing.CostModeAuto)
-	calc.ApplyAll(entries)
+	if !noArchive {
+		opts.archive = archive.New(filepath.Join(config.DataDir(), "archive.jsonl.gz"))
+	}
+	return opts
+}
 
-	// Apply time range filter
-	entries, err = domain.FilterByTimeRange(entries, since, until, tz)
+// runNoTUI streams every entry through dedup, project resolution, pricing
+// and the filters straight into the aggregate of the selected view, so
+// totals are exact for any history size.
+func runNoTUI(cfg config.Config, opts noTUIOptions) {
+	// Load timezone
+	tz, err := time.LoadLocation(cfg.General.Timezone)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing date filter: %v\n", err)
-		os.Exit(1)
+		tz = time.UTC
 	}
 
-	var data any
-	switch view {
+	var add func(domain.UsageEntry)
+	var addPrompt func(domain.Prompt) // set by views that need prompts
+	var result func() any
+	switch opts.view {
 	case "daily":
-		data = domain.AggregateDaily(entries, tz)
+		acc := domain.NewDailyAccumulator(tz)
+		add, result = acc.Add, func() any { return acc.Days() }
 	case "blocks":
-		data = domain.BuildBlocks(entries)
+		acc := domain.NewBlockAccumulator(domain.BlockOptions{Anchors: history.Starts(opts.windows.FiveHour()), Gaps: true})
+		add, result = acc.Add, func() any { return acc.Blocks() }
+	case "weekly":
+		acc := domain.NewWeeklyAccumulator(domain.WeeklyOptions{
+			Observed:  opts.windows.SevenDay(),
+			ResetDay:  cfg.Weekly.Weekday(),
+			ResetHour: cfg.Weekly.Hour(),
+			Location:  tz,
+		})
+		add, result = acc.Add, func() any { return acc.Windows() }
+	case "sessions":
+		acc := domain.NewSessionAccumulator()
+		add, result = acc.Add, func() any { return acc.Sessions() }
+	case "projects":
+		acc := domain.NewProjectAccumulator(tz)
+		add, result = acc.Add, func() any { return acc.Projects() }
+	case "branches", "versions":
+		key := domain.KeyBranch
+		if opts.view == "versions" {
+			key = domain.KeyVersion
+		}
+		acc := domain.NewGroupAccumulator(key)
+		add, result = acc.Add, func() any { return acc.Groups() }
+	case "forecast":
+		acc := domain.NewForecastAccumulator(domain.ForecastOptions{
+			Now:      time.Now(),
+			Location: tz,
+			Weekly: &domain.WeeklyOptions{
+				Observed:  opts.windows.SevenDay(),
+				ResetDay:  cfg.Weekly.Weekday(),
+				ResetHour: cfg.Weekly.Hour(),
+				Location:  tz,
+			},
+		})
+		add, result = acc.Add, func() any { return acc.Forecast() }
+	case "heatmap":
+		acc := domain.NewHeatmapAccumulator(tz)
+		add, result = acc.Add, func() any {
+			hm := acc.Heatmap()
+			return struct {
+				domain.Heatmap
+				Peaks []domain.HeatmapSlot
+			}{hm, hm.Peaks(10)}
+		}
+	case "value":
+		// A cut-off cycle would misstate its cost and break-even day
+		if opts.since != "" || opts.until != "" || !opts.meta.IsZero() || opts.filter != "" {
+			fmt.Fprintln(os.Stderr, "Note: --view value ignores the date range and filters")
+			opts.since, opts.until, opts.meta, opts.filter = "", "", domain.MetadataFilter{}, ""
+		}
+		acc := domain.NewValueAccumulator(domain.ValueOptions{
+			Plan:       cfg.Plan.Label(),
+			Price:      cfg.Plan.MonthlyPrice(),
+			BillingDay: cfg.Plan.Day(),
+			Location:   tz,
+			Now:        time.Now(),
+		})
+		add, result = acc.Add, func() any { return acc.Report() }
+	case "anomalies":
+		add, result = anomaliesReport(cfg, opts, tz)
+	case "tools":
+		add, result = toolsReport(tz)
+	case "cache":
+		opts.calc = loadCalculator()
+		add, result = cacheReport(opts, tz)
+	case "prompts":
+		acc := domain.NewPromptAccumulator()
+		add, addPrompt = acc.Add, acc.AddPrompt
+		result = func() any { return domain.TopPromptsBySession(acc.Prompts(), promptsPerSession) }
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown view: %s (use daily or blocks)\n", view)
+		fmt.Fprintf(os.Stderr, "Unknown view: %s (use daily, blocks, weekly, sessions, projects, heatmap, forecast, anomalies, value, cache, branches, versions, tools or prompts)\n", opts.view)
 		os.Exit(1)
 	}
+
+	streamEntries(cfg, opts, tz, add, addPrompt)
+
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
-	if err := enc.Encode(data); err != nil {
+	if err := enc.Encode(result()); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func defaultDataDir() string {
-	home, err := os.UserHomeDir()
+// streamEntries feeds every deduplicated and priced entry that passes the
+// filters of opts to add, and every prompt of the scanned logs to
+// addPrompt when it is set. Like parser.Dedup it keeps the earliest copy
+// of a duplicated entry, preferring the live one over the archive's; a
+// first pass over the logs and the archive only records the earliest
+// timestamp of every dedup key. Logs, parse index and archive are all
+// read a file or member at a time, so memory does not grow with the
+// number of entries beyond that per-key timestamp.
+func streamEntries(cfg config.Config, opts noTUIOptions, tz *time.Location, add func(domain.UsageEntry), addPrompt func(domain.Prompt)) {
+	timeRange, err := rangeContext(cfg).Parse(opts.since, opts.until)
 	if err != nil {
-		return filepath.Join(".", ".claude", "projects")
+		fmt.Fprintf(os.Stderr, "Error parsing date filter: %v\n", err)
+		os.Exit(1)
+	}
+	if opts.since != "" || opts.until != "" {
+		// Echo the resolved range on stderr so stdout stays parseable
+		fmt.Fprintf(os.Stderr, "Range: %s (%s)\n", timeRange, tz)
+	}
+	filter, err := domain.ParseFilterExpr(opts.filter, tz)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --filter: %v\n", err)
+		os.Exit(1)
+	}
+
+	calc := opts.calc
+	if calc == nil {
+		calc = loadCalculator()
+	}
+	resolver := project.NewResolver(cfg.Projects.Aliases)
+
+	// Scan and parse all JSONL files a file at a time. The first pass
+	// updates the on-disk index when enabled and the second reads the
+	// updated index back; without it every pass re-parses the logs.
+	scanOpts := parser.ScanOptions{Workers: cfg.General.ParseWorkers}
+	indexed := opts.indexPath != ""
+	passes := 0
+	eachFile := func(fn func(parser.FileResult)) {
+		passes++
+		switch {
+		case indexed && passes == 1:
+			if err := parser.ScanIndexed(context.Background(), opts.indexPath, opts.dataDirs, scanOpts, fn); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not save parse index: %v\n", err)
+				indexed = false
+			}
+		case indexed:
+			if err := parser.EachIndexed(opts.indexPath, fn); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not read parse index: %v\n", err)
+			}
+		default:
+			parser.ScanEach(context.Background(), opts.dataDirs, scanOpts, fn)
+		}
+	}
+
+	// First pass: find the earliest timestamp of every dedup key, so the
+	// copy kept here is the one parser.Dedup keeps in the TUI.
+	earliest := make(map[uint64]time.Time)
+	noteIn := func(m map[uint64]time.Time) func(domain.UsageEntry) {
+		return func(e domain.UsageEntry) {
+			if !e.HasDedupKey() {
+				return
+			}
+			h := e.KeyHash()
+			if t, ok := m[h]; !ok || e.Timestamp.Before(t) {
+				m[h] = e.Timestamp
+			}
+		}
+	}
+	note := noteIn(earliest)
+	// Tool calls of every keyed record, to fold the calls of a message's
+	// other content blocks onto the copy kept, like parser.Dedup does.
+	tools := make(map[uint64][]timedTools)
+	eachFile(func(f parser.FileResult) {
+		if addPrompt != nil {
+			for _, p := range f.Prompts {
+				addPrompt(p)
+			}
+		}
+		for _, e := range f.Entries {
+			note(e)
+			if e.HasDedupKey() && len(e.Tools) > 0 {
+				h := e.KeyHash()
+				tools[h] = append(tools[h], timedTools{e.Timestamp, e.Tools})
+			}
+		}
+	})
+	// Archived timestamps only count once the whole archive was read, so
+	// a failed read leaves the live entries untouched.
+	store := opts.archive
+	if store != nil {
+		archived := make(map[uint64]time.Time)
+		if err := store.Each(noteIn(archived)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read usage archive: %v\n", err)
+			store = nil
+		} else {
+			for h, t := range archived {
+				if live, ok := earliest[h]; !ok || t.Before(live) {
+					earliest[h] = t
+				}
+			}
+		}
+	}
+
+	// Second pass: emit the earliest copy of every entry. Live logs come
+	// before the archive, so of equally early copies the live one wins.
+	seen := make(domain.KeySet)
+	emit := func(e domain.UsageEntry) {
+		if e.HasDedupKey() {
+			// A key missing from the first pass was written in between
+			if t, ok := earliest[e.KeyHash()]; ok && !e.Timestamp.Equal(t) {
+				return
+			}
+		}
+		if !seen.Add(e) {
+			return
+		}
+		if e.HasDedupKey() {
+			e.Tools = foldedTools(e, tools[e.KeyHash()])
+		}
+		resolver.ApplyEntry(&e)
+		e.CostUSD = calc.Calculate(&e)
+		if timeRange.Contains(e.Timestamp) && opts.window.Contains(e.Timestamp) && opts.meta.Match(e) && filter.Match(e) {
+			add(e)
+		}
+	}
+	batch := newArchiveBatch(store)
+	eachFile(func(f parser.FileResult) {
+		for _, e := range f.Entries {
+			emit(e)
+		}
+		batch.Add(f.Entries)
+	})
+
+	// Add history pruned from the logs and archive anything new
+	if store != nil {
+		if err := store.Each(emit); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read usage archive: %v\n", err)
+		} else if err := batch.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not update usage archive: %v\n", err)
+		}
+	}
+}
+
+// timedTools are the tool calls of one record.
+type timedTools struct {
+	at    time.Time
+	names []string
+}
+
+// foldedTools returns the tool calls of the kept copy e of a message
+// together with those of its other records, in time order. Records at
+// e's timestamp are copies of e rather than other content blocks, so
+// their calls are not added again.
+func foldedTools(e domain.UsageEntry, records []timedTools) []string {
+	sort.SliceStable(records, func(i, j int) bool { return records[i].at.Before(records[j].at) })
+	names := e.Tools
+	for _, r := range records {
+		if !r.at.Equal(e.Timestamp) {
+			// Never append into a slice shared with a parsed entry
+			names = append(names[:len(names):len(names)], r.names...)
+		}
+	}
+	return names
+}
+
+// loadCalculator returns a calculator with the embedded prices overlaid
+// with LiteLLM's.
+func loadCalculator() *pricing.Calculator {
+	table, err := pricing.LoadDefault()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading pricing: %v\n", err)
+		os.Exit(1)
+	}
+	if fetched, err := pricing.FetchLiteLLM(context.Background()); err == nil {
+		table.Merge(fetched)
+	}
+	return pricing.NewCalculator(table, pricing.CostModeAuto)
+}
+
+// promptsPerSession is the number of prompts --view prompts lists per
+// session.
+const promptsPerSession = 10
+
+// toolsReport returns the add and result functions of --view tools: tool
+// usage in total and per session, project and day. Per-block usage is
+// part of --view blocks.
+func toolsReport(tz *time.Location) (func(domain.UsageEntry), func() any) {
+	total := domain.NewToolAccumulator(domain.KeyAll)
+	sessions := domain.NewToolAccumulator(domain.KeySession)
+	projects := domain.NewToolAccumulator(domain.KeyProject)
+	days := domain.NewToolAccumulator(domain.KeyDay(tz))
+	add := func(e domain.UsageEntry) {
+		for _, acc := range []*domain.ToolAccumulator{total, sessions, projects, days} {
+			acc.Add(e)
+		}
+	}
+	result := func() any {
+		var report struct {
+			Total    domain.ToolGroup
+			Sessions []domain.ToolGroup
+			Projects []domain.ToolGroup
+			Days     []domain.ToolGroup
+		}
+		if groups := total.Groups(); len(groups) > 0 {
+			report.Total = groups[0]
+		}
+		report.Sessions = sessions.Groups()
+		report.Projects = projects.Groups()
+		report.Days = days.Groups()
+		return report
+	}
+	return add, result
+}
+
+// anomaliesReport returns the add and result functions of --view
+// anomalies: the outlying days, blocks and sessions, most recent first.
+func anomaliesReport(cfg config.Config, opts noTUIOptions, tz *time.Location) (func(domain.UsageEntry), func() any) {
+	days := domain.NewDailyAccumulator(tz)
+	blocks := domain.NewBlockAccumulator(domain.BlockOptions{Anchors: history.Starts(opts.windows.FiveHour())})
+	sessions := domain.NewSessionAccumulator()
+	add := func(e domain.UsageEntry) {
+		days.Add(e)
+		blocks.Add(e)
+		sessions.Add(e)
+	}
+	detect := domain.AnomalyOptions{Method: domain.AnomalyMAD, K: cfg.Anomalies.K, Window: cfg.Anomalies.Window}
+	if cfg.Anomalies.Stddev() {
+		detect.Method = domain.AnomalyStdDev
+	}
+	result := func() any {
+		return domain.DetectAnomalies(days.Days(), blocks.Blocks(), sessions.Sessions(), tz, detect)
+	}
+	return add, result
+}
+
+// cacheReport returns the add and result functions of --view cache: cache
+// efficiency and wasted cache writes in total and per session, block,
+// project and day.
+func cacheReport(opts noTUIOptions, tz *time.Location) (func(domain.UsageEntry), func() any) {
+	acc := domain.NewCacheAccumulator(domain.CacheOptions{
+		Location: tz,
+		Rates:    opts.calc.CacheRates,
+		Blocks:   domain.BlockOptions{Anchors: history.Starts(opts.windows.FiveHour())},
+		Now:      time.Now(),
+	})
+	return acc.Add, func() any { return acc.Report() }
+}
+
+// archiveBatch appends entries to the archive in bounded batches. Already
+// archived entries are skipped by the store. A nil store discards them.
+type archiveBatch struct {
+	store   *archive.Store
+	entries []domain.UsageEntry
+	err     error // first failed append; later batches are dropped
+}
+
+// archiveBatchSize is the number of entries written per archive member.
+const archiveBatchSize = 10_000
+
+func newArchiveBatch(store *archive.Store) *archiveBatch {
+	return &archiveBatch{store: store}
+}
+
+// Add queues entries, appending a batch whenever it is full.
+func (b *archiveBatch) Add(entries []domain.UsageEntry) {
+	if b.store == nil || b.err != nil {
+		return
+	}
+	for _, e := range entries {
+		b.entries = append(b.entries, e)
+		if len(b.entries) == archiveBatchSize {
+			_, b.err = b.store.Append(b.entries)
+			b.entries = b.entries[:0]
+			if b.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Flush appends the queued entries and returns the first append error.
+func (b *archiveBatch) Flush() error {
+	if b.store == nil || b.err != nil {
+		return b.err
 	}
-	return filepath.Join(home, ".claude", "projects")
+	_, b.err = b.store.Append(b.entries)
+	b.entries = nil
+	return b.err
 }
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anomredux/claude-smi/internal/config"
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+// runQuery implements "claude-smi query": it groups the usage by the
+// --group-by dimensions, computes the --metrics of every group and prints
+// them as a table or JSON. It returns the process exit code.
+func runQuery(args []string) int {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	var (
+		configPath = fs.String("config", config.DefaultPath(), "config file path")
+		dataDir    = fs.String("data-dir", "", "Claude data directories, comma-separated (default: $CLAUDE_CONFIG_DIR or auto-discover)")
+		timezone   = fs.String("timezone", "", "override timezone (e.g., Asia/Seoul)")
+		since      = fs.String("since", "", rangeFlagHelp("start"))
+		until      = fs.String("until", "", rangeFlagHelp("end"))
+		filterExpr = fs.String("filter", "", `filter expression, e.g. "model~opus and cost>0.5"`)
+		groupBy    = fs.String("group-by", "", "dimensions, comma-separated: "+domain.JoinNames(domain.Dimensions))
+		metrics    = fs.String("metrics", "tokens,cost", "metrics, comma-separated: "+domain.JoinNames(domain.Metrics))
+		sortBy     = fs.String("sort", "", "order groups by this metric, largest first (default: by group)")
+		limit      = fs.Int("limit", 0, "print at most this many groups (0: all)")
+		jsonOut    = fs.Bool("json", false, "output the result as JSON")
+		noCache    = fs.Bool("no-cache", false, "parse all logs from scratch without the on-disk index")
+		noArchive  = fs.Bool("no-archive", false, "neither read nor extend the usage archive")
+	)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 2
+	}
+	if *timezone != "" {
+		cfg.General.Timezone = *timezone
+	}
+	tz, err := time.LoadLocation(cfg.General.Timezone)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid timezone: %s\n", cfg.General.Timezone)
+		return 2
+	}
+
+	q, err := domain.ParseQuery(*groupBy, *metrics, tz)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid query: %v\n", err)
+		return 2
+	}
+	if *sortBy != "" {
+		if q.SortBy, err = domain.ParseMetric(*sortBy); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --sort: %v\n", err)
+			return 2
+		}
+	}
+	q.Limit = *limit
+
+	opts := sourceOptions(cfg, *dataDir, *noCache, *noArchive)
+	opts.since, opts.until = *since, *until
+	opts.filter = *filterExpr
+	acc := domain.NewQueryAccumulator(q)
+	streamEntries(cfg, opts, tz, acc.Add, nil)
+	result := acc.Result()
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			return 2
+		}
+		return 0
+	}
+	writeQueryResult(os.Stdout, result)
+	return 0
+}
+
+// writeQueryResult prints one row per group: the group keys, left
+// aligned, followed by the metrics.
+func writeQueryResult(w io.Writer, res domain.QueryResult) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	var header []string
+	for _, d := range res.GroupBy {
+		header = append(header, strings.ToUpper(string(d)))
+	}
+	for _, m := range res.Metrics {
+		header = append(header, strings.ToUpper(string(m)))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range res.Rows {
+		cells := make([]string, 0, len(header))
+		for _, k := range row.Keys {
+			if k == "" {
+				k = "-"
+			}
+			cells = append(cells, k)
+		}
+		for i, m := range res.Metrics {
+			cells = append(cells, formatMetric(m, row.Values[i]))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	tw.Flush()
+}
+
+// formatMetric renders a metric value: dollars for cost, a percentage for
+// the cache hit ratio and whole numbers otherwise.
+func formatMetric(m domain.Metric, v float64) string {
+	switch m {
+	case domain.MetricCost:
+		return fmt.Sprintf("$%.2f", v)
+	case domain.MetricCacheHit:
+		return fmt.Sprintf("%.1f%%", v*100)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
@@ -72,7 +72,7 @@ func main() {
 		},
 	}
 
-	blocks := domain.BuildBlocks(entries)
+	blocks := domain.BuildBlocks(entries, domain.BlockOptions{Gaps: true})
 	lv.SetData(entries, blocks, nil)
 
 	fmt.Println(lv.Render(100, 40, false))
@@ -0,0 +1,195 @@
+// Package archive keeps every deduplicated usage entry claude-smi has seen,
+// so reports stay complete after Claude Code prunes its transcripts.
+//
+// The archive is a single append-only file of concatenated gzip members,
+// each holding JSON lines of domain.UsageEntry. Appending never rewrites
+// existing data; a member torn by a crash is cut off on the next load.
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+// Store is an append-only archive file. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu     sync.Mutex
+	keys   domain.KeySet // dedup keys already archived
+	loaded bool
+}
+
+// New returns a store backed by path. Nothing is read until Load.
+func New(path string) *Store {
+	return &Store{path: path, keys: make(domain.KeySet)}
+}
+
+// Load reads all archived entries. A missing file yields no entries.
+// A trailing member that cannot be decoded (e.g. a crash mid-append) is
+// truncated so later appends stay readable.
+func (s *Store) Load() ([]domain.UsageEntry, error) {
+	var entries []domain.UsageEntry
+	err := s.Each(func(e domain.UsageEntry) {
+		entries = append(entries, e)
+	})
+	return entries, err
+}
+
+// Each is like Load but passes the archived entries to fn one at a time
+// instead of returning them, so the archive never has to fit in memory.
+// Entries of a torn trailing member are never passed.
+func (s *Store) Each(fn func(domain.UsageEntry)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	keys := make(domain.KeySet)
+	valid, readErr := readMembers(f, func(member []domain.UsageEntry) {
+		for _, e := range member {
+			keys.Add(e)
+			fn(e)
+		}
+	})
+	if readErr != nil {
+		if err := f.Truncate(valid); err != nil {
+			return fmt.Errorf("repair archive: %w", err)
+		}
+	}
+
+	s.keys = keys
+	s.loaded = true
+	return nil
+}
+
+// Append archives the entries whose dedup key has not been archived yet
+// and returns how many were written. Entries without a key are skipped
+// because they could never be deduplicated. Load must be called first.
+func (s *Store) Append(entries []domain.UsageEntry) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		return 0, fmt.Errorf("append to archive before load")
+	}
+
+	var fresh []domain.UsageEntry
+	for _, e := range entries {
+		if e.HasDedupKey() && s.keys.Add(e) {
+			fresh = append(fresh, e)
+		}
+	}
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return 0, fmt.Errorf("create archive dir: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("open archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+	for _, e := range fresh {
+		if err := enc.Encode(e); err != nil {
+			return 0, fmt.Errorf("encode archive entry: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("write archive: %w", err)
+	}
+	return len(fresh), nil
+}
+
+// Merge returns live plus every archived entry whose dedup key does not
+// appear in live. Live entries win because they may carry fresher data.
+func Merge(live, archived []domain.UsageEntry) []domain.UsageEntry {
+	if len(archived) == 0 {
+		return live
+	}
+	seen := make(domain.KeySet, len(live))
+	for _, e := range live {
+		seen.Add(e)
+	}
+	merged := make([]domain.UsageEntry, 0, len(live)+len(archived))
+	merged = append(merged, live...)
+	for _, e := range archived {
+		if !seen.Has(e) {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
+
+// readMembers decodes gzip members one at a time, passes the entries of
+// every complete member to fn and returns the byte length they span.
+func readMembers(r io.Reader, fn func([]domain.UsageEntry)) (int64, error) {
+	cr := &countingReader{br: bufio.NewReader(r)}
+	var valid int64
+
+	zr, err := gzip.NewReader(cr)
+	for err == nil {
+		zr.Multistream(false)
+		var member []domain.UsageEntry
+		dec := json.NewDecoder(zr)
+		for {
+			var e domain.UsageEntry
+			if err = dec.Decode(&e); err != nil {
+				break
+			}
+			member = append(member, e)
+		}
+		if err != io.EOF {
+			return valid, err
+		}
+		fn(member)
+		valid = cr.n
+		err = zr.Reset(cr)
+	}
+	if err == io.EOF {
+		return valid, nil
+	}
+	return valid, err
+}
+
+// countingReader counts bytes consumed by the gzip decoder. It implements
+// io.ByteReader so the decoder never reads past the end of a member.
+type countingReader struct {
+	br *bufio.Reader
+	n  int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.br.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.br.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
@@ -0,0 +1,136 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+func entry(msg string, input int) domain.UsageEntry {
+	return domain.UsageEntry{
+		Timestamp:   time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC),
+		MessageID:   msg,
+		RequestID:   "req_" + msg,
+		InputTokens: input,
+		Model:       "claude-opus-4-6",
+	}
+}
+
+func TestStore_AppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "archive.jsonl.gz")
+
+	s := New(path)
+	if entries, err := s.Load(); err != nil || len(entries) != 0 {
+		t.Fatalf("Load on missing file = (%d, %v), want (0, nil)", len(entries), err)
+	}
+
+	n, err := s.Append([]domain.UsageEntry{entry("m1", 100), entry("m2", 200)})
+	if err != nil || n != 2 {
+		t.Fatalf("Append = (%d, %v), want (2, nil)", n, err)
+	}
+	// Already archived keys and keyless entries are skipped.
+	n, err = s.Append([]domain.UsageEntry{entry("m1", 100), entry("m3", 300), {InputTokens: 5}})
+	if err != nil || n != 1 {
+		t.Fatalf("second Append = (%d, %v), want (1, nil)", n, err)
+	}
+
+	entries, err := New(path).Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if entries[2].MessageID != "m3" || entries[2].InputTokens != 300 {
+		t.Errorf("third entry = %+v, want m3 with 300 input tokens", entries[2])
+	}
+	if !entries[0].Timestamp.Equal(entry("m1", 0).Timestamp) {
+		t.Errorf("timestamp = %v, not preserved", entries[0].Timestamp)
+	}
+}
+
+func TestStore_AppendBeforeLoad(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "archive.jsonl.gz"))
+	if _, err := s.Append([]domain.UsageEntry{entry("m1", 1)}); err == nil {
+		t.Error("expected error when appending before Load")
+	}
+}
+
+func TestStore_RepairsTornMember(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl.gz")
+	s := New(path)
+	s.Load()
+	if _, err := s.Append([]domain.UsageEntry{entry("m1", 100)}); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	good := info.Size()
+
+	// Simulate a crash halfway through a second append.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0x1f, 0x8b, 0x08, 0x00, 0x00})
+	f.Close()
+
+	s = New(path)
+	entries, err := s.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if info, _ := os.Stat(path); info.Size() != good {
+		t.Errorf("archive size = %d, want truncated to %d", info.Size(), good)
+	}
+
+	if _, err := s.Append([]domain.UsageEntry{entry("m2", 200)}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := New(path).Load(); len(entries) != 2 {
+		t.Errorf("got %d entries after repair + append, want 2", len(entries))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	live := []domain.UsageEntry{entry("m1", 111), entry("m2", 200)}
+	archived := []domain.UsageEntry{entry("m1", 100), entry("m0", 50)}
+
+	merged := Merge(live, archived)
+	if len(merged) != 3 {
+		t.Fatalf("got %d entries, want 3", len(merged))
+	}
+	for _, e := range merged {
+		if e.MessageID == "m1" && e.InputTokens != 111 {
+			t.Errorf("m1 InputTokens = %d, want live value 111", e.InputTokens)
+		}
+	}
+}
+
+func TestStore_EachLoadsKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl.gz")
+	s := New(path)
+	s.Load()
+	if _, err := s.Append([]domain.UsageEntry{entry("m1", 100), entry("m2", 200)}); err != nil {
+		t.Fatal(err)
+	}
+
+	s = New(path)
+	var got []string
+	if err := s.Each(func(e domain.UsageEntry) { got = append(got, e.MessageID) }); err != nil {
+		t.Fatalf("Each failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "m1" || got[1] != "m2" {
+		t.Errorf("Each visited %v, want [m1 m2]", got)
+	}
+	// Each counts as a load: known keys are skipped on append.
+	n, err := s.Append([]domain.UsageEntry{entry("m2", 200), entry("m3", 300)})
+	if err != nil || n != 1 {
+		t.Errorf("Append after Each = (%d, %v), want (1, nil)", n, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -11,12 +13,19 @@ import (
 type Config struct {
 	General       GeneralConfig       `toml:"general"`
 	Notifications NotificationsConfig `toml:"notifications"`
+	Projects      ProjectsConfig      `toml:"projects"`
+	Weekly        WeeklyConfig        `toml:"weekly"`
+	Plan          PlanConfig          `toml:"plan"`
+	Anomalies     AnomaliesConfig     `toml:"anomalies"`
+	Budgets       BudgetsConfig       `toml:"budgets"`
 }
 
 type GeneralConfig struct {
-	Interval int    `toml:"interval"`
-	Timezone string `toml:"timezone"`
-	Language string `toml:"language"`
+	Interval     int      `toml:"interval"`
+	Timezone     string   `toml:"timezone"`
+	Language     string   `toml:"language"`
+	ParseWorkers int      `toml:"parse_workers"` // 0 = one per CPU
+	DataDirs     []string `toml:"data_dirs"`     // Claude config or projects dirs; empty = auto-discover
 }
 
 type NotificationsConfig struct {
@@ -24,6 +33,146 @@ type NotificationsConfig struct {
 	Bell    bool `toml:"bell"`
 }
 
+type ProjectsConfig struct {
+	Aliases map[string]string `toml:"aliases"` // project root -> display name
+}
+
+// WeeklyConfig places weekly windows until the usage API has reported a
+// weekly reset.
+type WeeklyConfig struct {
+	ResetDay  string `toml:"reset_day"`  // weekday name, e.g. "monday" or "mon"
+	ResetHour int    `toml:"reset_hour"` // 0-23, in the configured timezone
+}
+
+// Weekday returns the configured reset day, Monday if it is not a valid
+// weekday name.
+func (w WeeklyConfig) Weekday() time.Weekday {
+	name := strings.ToLower(strings.TrimSpace(w.ResetDay))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || (len(name) >= 3 && strings.HasPrefix(full, name)) {
+			return d
+		}
+	}
+	return time.Monday
+}
+
+// Hour returns the configured reset hour, clamped to 0-23.
+func (w WeeklyConfig) Hour() int {
+	return min(max(w.ResetHour, 0), 23)
+}
+
+// PlanConfig describes the subscription plan.
+type PlanConfig struct {
+	Name       string  `toml:"name"`        // pro, max5x, max20x, team, team-premium or custom
+	Price      float64 `toml:"price"`       // monthly fee in USD; overrides the plan's list price
+	BillingDay int     `toml:"billing_day"` // day of the month a billing cycle starts, 1-31
+}
+
+// planPrices lists the monthly list prices of the known plans in USD;
+// team plans are per seat, billed monthly.
+var planPrices = map[string]struct {
+	label string
+	price float64
+}{
+	"pro":          {"Pro", 20},
+	"max5x":        {"Max 5x", 100},
+	"max20x":       {"Max 20x", 200},
+	"team":         {"Team seat", 30},
+	"team-premium": {"Team Premium seat", 150},
+}
+
+// planKey normalizes a plan name, so "Max 5x" and "max-5x" both read
+// max5x.
+func (p PlanConfig) planKey() string {
+	key := strings.ToLower(strings.TrimSpace(p.Name))
+	key = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(key)
+	if key == "teampremium" {
+		return "team-premium"
+	}
+	return key
+}
+
+// Label returns the display name of the plan, empty without one.
+func (p PlanConfig) Label() string {
+	if known, ok := planPrices[p.planKey()]; ok {
+		return known.label
+	}
+	return strings.TrimSpace(p.Name)
+}
+
+// MonthlyPrice returns the configured price, or else the list price of
+// the plan; 0 when neither is known.
+func (p PlanConfig) MonthlyPrice() float64 {
+	if p.Price > 0 {
+		return p.Price
+	}
+	return planPrices[p.planKey()].price
+}
+
+// Day returns the billing day, clamped to 1-31.
+func (p PlanConfig) Day() int {
+	return min(max(p.BillingDay, 1), 31)
+}
+
+// AnomaliesConfig tunes the detector that flags unusually expensive days,
+// blocks and sessions.
+type AnomaliesConfig struct {
+	Method string  `toml:"method"` // "mad" (median + k·MAD) or "stddev" (mean + k·σ)
+	K      float64 `toml:"k"`      // spreads above the baseline that make an outlier
+	Window int     `toml:"window"` // earlier days, blocks or sessions in the baseline
+}
+
+// Stddev reports whether the baseline is the mean and standard deviation
+// rather than the median and MAD.
+func (a AnomaliesConfig) Stddev() bool {
+	switch strings.ToLower(strings.TrimSpace(a.Method)) {
+	case "stddev", "sigma", "mean":
+		return true
+	}
+	return false
+}
+
+// BudgetsConfig sets spend limits on API-equivalent cost and tokens. The
+// top-level limits cover all usage; Limits adds limits for one project or
+// model. Zero means no limit.
+type BudgetsConfig struct {
+	DailyCost     float64       `toml:"daily_cost"`
+	WeeklyCost    float64       `toml:"weekly_cost"`
+	MonthlyCost   float64       `toml:"monthly_cost"`
+	DailyTokens   int           `toml:"daily_tokens"`
+	WeeklyTokens  int           `toml:"weekly_tokens"`
+	MonthlyTokens int           `toml:"monthly_tokens"`
+	Limits        []BudgetLimit `toml:"limits"`
+}
+
+// BudgetLimit limits one period, optionally for a project or model.
+type BudgetLimit struct {
+	Period  string  `toml:"period"` // "day", "week" or "month"
+	Cost    float64 `toml:"cost"`
+	Tokens  int     `toml:"tokens"`
+	Project string  `toml:"project"` // project name or root; empty = all
+	Model   string  `toml:"model"`   // part of the model name; empty = all
+}
+
+// All returns the top-level limits, one per period that has any,
+// followed by Limits. Limits without a cost or token limit are dropped.
+func (b BudgetsConfig) All() []BudgetLimit {
+	all := []BudgetLimit{
+		{Period: "day", Cost: b.DailyCost, Tokens: b.DailyTokens},
+		{Period: "week", Cost: b.WeeklyCost, Tokens: b.WeeklyTokens},
+		{Period: "month", Cost: b.MonthlyCost, Tokens: b.MonthlyTokens},
+	}
+	all = append(all, b.Limits...)
+	limits := all[:0]
+	for _, l := range all {
+		if l.Cost > 0 || l.Tokens > 0 {
+			limits = append(limits, l)
+		}
+	}
+	return limits
+}
+
 func DefaultConfig() Config {
 	return Config{
 		General: GeneralConfig{
@@ -35,6 +184,17 @@ func DefaultConfig() Config {
 			Enabled: true,
 			Bell:    true,
 		},
+		Weekly: WeeklyConfig{
+			ResetDay: "monday",
+		},
+		Plan: PlanConfig{
+			BillingDay: 1,
+		},
+		Anomalies: AnomaliesConfig{
+			Method: "mad",
+			K:      3.5,
+			Window: 30,
+		},
 	}
 }
 
@@ -46,6 +206,115 @@ func DefaultPath() string {
 	return filepath.Join(home, ".config", "claude-smi", "config.toml")
 }
 
+// CacheDir returns the directory for claude-smi's disposable caches.
+func CacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(".", ".cache", "claude-smi")
+	}
+	return filepath.Join(dir, "claude-smi")
+}
+
+// DataDir returns the directory for data claude-smi owns and must keep,
+// such as the usage archive. It follows XDG_DATA_HOME when set.
+func DataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "claude-smi")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".", ".local", "share", "claude-smi")
+	}
+	return filepath.Join(home, ".local", "share", "claude-smi")
+}
+
+// ClaudeDataDirs returns the Claude Code project log directories to read.
+// The first non-empty list wins: explicit (the --data-dir flag), the
+// comma-separated CLAUDE_CONFIG_DIR, then configured. Without any, the
+// standard locations ~/.config/claude and ~/.claude are used if present.
+// Each dir may name a Claude config dir or its projects subdirectory.
+func ClaudeDataDirs(explicit, configured []string) []string {
+	dirs := explicit
+	if len(dirs) == 0 {
+		dirs = SplitList(os.Getenv("CLAUDE_CONFIG_DIR"))
+	}
+	if len(dirs) == 0 {
+		dirs = configured
+	}
+	if len(dirs) == 0 {
+		dirs = defaultClaudeDirs()
+	}
+
+	seen := make(map[string]struct{}, len(dirs))
+	var out []string
+	for _, dir := range dirs {
+		dir = projectsDir(filepath.Clean(expandHome(dir)))
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+		seen[dir] = struct{}{}
+		out = append(out, dir)
+	}
+	return out
+}
+
+// defaultClaudeDirs returns the standard Claude config dirs that hold
+// project logs, falling back to ~/.claude when none exist yet.
+func defaultClaudeDirs() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return []string{filepath.Join(".", ".claude")}
+	}
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	var found []string
+	for _, dir := range []string{filepath.Join(xdg, "claude"), filepath.Join(home, ".claude")} {
+		if isDir(filepath.Join(dir, "projects")) {
+			found = append(found, dir)
+		}
+	}
+	if len(found) == 0 {
+		return []string{filepath.Join(home, ".claude")}
+	}
+	return found
+}
+
+// projectsDir maps a Claude config dir to its projects subdirectory and
+// returns any other dir unchanged.
+func projectsDir(dir string) string {
+	if filepath.Base(dir) != "projects" && isDir(filepath.Join(dir, "projects")) {
+		return filepath.Join(dir, "projects")
+	}
+	return dir
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+// SplitList splits a comma-separated list, dropping empty items.
+func SplitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func Load(path string) (Config, error) {
 	cfg := DefaultConfig()
 	if _, err := os.Stat(path); os.IsNotExist(err) {
@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestLoadDefault(t *testing.T) {
@@ -74,3 +76,144 @@ func TestDefaultPath_NotEmpty(t *testing.T) {
 		t.Error("DefaultPath should not be empty")
 	}
 }
+
+func TestClaudeDataDirs(t *testing.T) {
+	base := t.TempDir()
+	acct1 := filepath.Join(base, "acct1")
+	acct2 := filepath.Join(base, "acct2")
+	for _, dir := range []string{acct1, acct2} {
+		if err := os.MkdirAll(filepath.Join(dir, "projects"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plain := filepath.Join(base, "logs")
+	p1, p2 := filepath.Join(acct1, "projects"), filepath.Join(acct2, "projects")
+
+	t.Setenv("CLAUDE_CONFIG_DIR", acct1+", "+acct2)
+
+	tests := []struct {
+		name                 string
+		explicit, configured []string
+		want                 []string
+	}{
+		{"explicit wins", []string{plain, p1, acct1}, []string{acct2}, []string{plain, p1}},
+		{"env before config", nil, []string{plain}, []string{p1, p2}},
+	}
+	for _, tt := range tests {
+		got := ClaudeDataDirs(tt.explicit, tt.configured)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ClaudeDataDirs = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	t.Setenv("CLAUDE_CONFIG_DIR", "")
+	if got := ClaudeDataDirs(nil, []string{acct2}); !slices.Equal(got, []string{p2}) {
+		t.Errorf("configured: ClaudeDataDirs = %v, want %v", got, []string{p2})
+	}
+}
+
+func TestWeeklyConfig(t *testing.T) {
+	tests := []struct {
+		day  string
+		want time.Weekday
+	}{
+		{"monday", time.Monday},
+		{"Thu", time.Thursday},
+		{" SUNDAY ", time.Sunday},
+		{"", time.Monday},
+		{"someday", time.Monday},
+		{"t", time.Monday},
+	}
+	for _, tt := range tests {
+		if got := (WeeklyConfig{ResetDay: tt.day}).Weekday(); got != tt.want {
+			t.Errorf("Weekday(%q) = %v, want %v", tt.day, got, tt.want)
+		}
+	}
+	if got := (WeeklyConfig{ResetHour: 30}).Hour(); got != 23 {
+		t.Errorf("Hour(30) = %d, want 23", got)
+	}
+}
+
+func TestPlanConfig_Day(t *testing.T) {
+	for _, tt := range []struct{ day, want int }{{0, 1}, {15, 15}, {40, 31}} {
+		if got := (PlanConfig{BillingDay: tt.day}).Day(); got != tt.want {
+			t.Errorf("Day(%d) = %d, want %d", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestAnomaliesConfig_Stddev(t *testing.T) {
+	for _, tt := range []struct {
+		method string
+		want   bool
+	}{{"mad", false}, {"", false}, {"StdDev", true}, {"sigma", true}} {
+		if got := (AnomaliesConfig{Method: tt.method}).Stddev(); got != tt.want {
+			t.Errorf("Stddev(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestBudgetsConfig_All(t *testing.T) {
+	b := BudgetsConfig{
+		DailyCost:     20,
+		MonthlyTokens: 1_000_000,
+		Limits: []BudgetLimit{
+			{Period: "month", Cost: 50, Project: "mono"},
+			{Period: "week", Model: "opus"}, // limits nothing
+		},
+	}
+	got := b.All()
+	want := []BudgetLimit{
+		{Period: "day", Cost: 20},
+		{Period: "month", Tokens: 1_000_000},
+		{Period: "month", Cost: 50, Project: "mono"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("All = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_Budgets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	data := `[budgets]
+monthly_cost = 300
+
+[[budgets.limits]]
+period = "week"
+cost = 40
+model = "opus"
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Budgets.MonthlyCost != 300 || len(cfg.Budgets.Limits) != 1 || cfg.Budgets.Limits[0].Model != "opus" {
+		t.Errorf("Budgets = %+v", cfg.Budgets)
+	}
+}
+
+func TestPlanConfig_Price(t *testing.T) {
+	tests := []struct {
+		plan  PlanConfig
+		label string
+		price float64
+	}{
+		{PlanConfig{Name: "max20x"}, "Max 20x", 200},
+		{PlanConfig{Name: "Max 5x"}, "Max 5x", 100},
+		{PlanConfig{Name: "team_premium"}, "Team Premium seat", 150},
+		{PlanConfig{Name: "pro", Price: 17}, "Pro", 17},
+		{PlanConfig{Name: "Enterprise", Price: 60}, "Enterprise", 60},
+		{PlanConfig{}, "", 0},
+	}
+	for _, tt := range tests {
+		if got := tt.plan.Label(); got != tt.label {
+			t.Errorf("Label(%q) = %q, want %q", tt.plan.Name, got, tt.label)
+		}
+		if got := tt.plan.MonthlyPrice(); got != tt.price {
+			t.Errorf("MonthlyPrice(%+v) = %v, want %v", tt.plan, got, tt.price)
+		}
+	}
+}
@@ -6,53 +6,55 @@ import (
 )
 
 type DailyAggregate struct {
-	Date                string // "2006-01-02"
-	InputTokens         int
-	OutputTokens        int
-	CacheCreationTokens int
-	CacheReadTokens     int
-	TotalCost           float64
-	EntriesCount        int
-}
-
-// TotalTokens returns the sum of all token types for this day.
-func (d DailyAggregate) TotalTokens() int {
-	return d.InputTokens + d.OutputTokens + d.CacheCreationTokens + d.CacheReadTokens
+	Date string // "2006-01-02"
+	Totals
+	EntriesCount int
 }
 
 type MonthlyAggregate struct {
-	Month                   string                 // "2006-01"
-	Days                    map[int]DailyAggregate // day number -> aggregate
-	TotalCost               float64
-	TotalTokens             int
-	TotalInputTokens        int
-	TotalOutputTokens       int
-	TotalCacheCreation      int
-	TotalCacheRead          int
-	TotalCalls              int
+	Month string                 // "2006-01"
+	Days  map[int]DailyAggregate // day number -> aggregate
+	Totals
+	TotalCalls int
 }
 
 // AggregateDaily groups entries by date in the given timezone.
 func AggregateDaily(entries []UsageEntry, tz *time.Location) []DailyAggregate {
-	groups := make(map[string]*DailyAggregate)
-
+	acc := NewDailyAccumulator(tz)
 	for _, e := range entries {
-		key := e.Timestamp.In(tz).Format("2006-01-02")
-		agg, ok := groups[key]
-		if !ok {
-			agg = &DailyAggregate{Date: key}
-			groups[key] = agg
-		}
-		agg.InputTokens += e.InputTokens
-		agg.OutputTokens += e.OutputTokens
-		agg.CacheCreationTokens += e.CacheCreationTokens
-		agg.CacheReadTokens += e.CacheReadTokens
-		agg.TotalCost += e.CostUSD
-		agg.EntriesCount++
+		acc.Add(e)
+	}
+	return acc.Days()
+}
+
+// DailyAccumulator builds daily aggregates one entry at a time, holding
+// one total per day.
+type DailyAccumulator struct {
+	tz     *time.Location
+	groups map[string]*DailyAggregate
+}
+
+// NewDailyAccumulator returns an empty accumulator grouping by date in tz.
+func NewDailyAccumulator(tz *time.Location) *DailyAccumulator {
+	return &DailyAccumulator{tz: tz, groups: make(map[string]*DailyAggregate)}
+}
+
+// Add accumulates one entry.
+func (a *DailyAccumulator) Add(e UsageEntry) {
+	key := e.Timestamp.In(a.tz).Format("2006-01-02")
+	agg, ok := a.groups[key]
+	if !ok {
+		agg = &DailyAggregate{Date: key}
+		a.groups[key] = agg
 	}
+	agg.Add(e)
+	agg.EntriesCount++
+}
 
-	result := make([]DailyAggregate, 0, len(groups))
-	for _, agg := range groups {
+// Days returns the aggregates, newest date first.
+func (a *DailyAccumulator) Days() []DailyAggregate {
+	result := make([]DailyAggregate, 0, len(a.groups))
+	for _, agg := range a.groups {
 		result = append(result, *agg)
 	}
 	sort.Slice(result, func(i, j int) bool {
@@ -77,22 +79,94 @@ func AggregateMonthly(entries []UsageEntry, tz *time.Location, year int, month t
 		day := local.Day()
 		d := agg.Days[day]
 		d.Date = local.Format("2006-01-02")
-		d.InputTokens += e.InputTokens
-		d.OutputTokens += e.OutputTokens
-		d.CacheCreationTokens += e.CacheCreationTokens
-		d.CacheReadTokens += e.CacheReadTokens
-		d.TotalCost += e.CostUSD
+		d.Add(e)
 		d.EntriesCount++
 		agg.Days[day] = d
 
-		agg.TotalCost += e.CostUSD
-		agg.TotalTokens += e.TotalTokens()
-		agg.TotalInputTokens += e.InputTokens
-		agg.TotalOutputTokens += e.OutputTokens
-		agg.TotalCacheCreation += e.CacheCreationTokens
-		agg.TotalCacheRead += e.CacheReadTokens
+		agg.Add(e)
 		agg.TotalCalls++
 	}
 
 	return agg
 }
+
+// GroupAggregate holds usage totals for entries sharing a key, such as a
+// git branch or CLI version.
+type GroupAggregate struct {
+	Key string
+	Totals
+	EntriesCount      int
+	SidechainCount    int
+	WebSearchRequests int
+	WebFetchRequests  int
+}
+
+// KeyFunc extracts a grouping key from an entry.
+type KeyFunc func(UsageEntry) string
+
+// Grouping keys for transcript metadata. Missing values group under "".
+var (
+	KeyBranch    KeyFunc = func(e UsageEntry) string { return e.GitBranch }
+	KeyVersion   KeyFunc = func(e UsageEntry) string { return e.Version }
+	KeySidechain KeyFunc = func(e UsageEntry) string {
+		if e.IsSidechain {
+			return "sidechain"
+		}
+		return "main"
+	}
+)
+
+// AggregateBy groups entries by key, sorted by cost descending
+// (ties broken by key).
+func AggregateBy(entries []UsageEntry, key KeyFunc) []GroupAggregate {
+	acc := NewGroupAccumulator(key)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Groups()
+}
+
+// GroupAccumulator builds group aggregates one entry at a time, holding
+// one total per key.
+type GroupAccumulator struct {
+	key    KeyFunc
+	groups map[string]*GroupAggregate
+}
+
+// NewGroupAccumulator returns an empty accumulator grouping by key.
+func NewGroupAccumulator(key KeyFunc) *GroupAccumulator {
+	return &GroupAccumulator{key: key, groups: make(map[string]*GroupAggregate)}
+}
+
+// Add accumulates one entry.
+func (a *GroupAccumulator) Add(e UsageEntry) {
+	k := a.key(e)
+	agg, ok := a.groups[k]
+	if !ok {
+		agg = &GroupAggregate{Key: k}
+		a.groups[k] = agg
+	}
+	agg.Add(e)
+	agg.EntriesCount++
+	if e.IsSidechain {
+		agg.SidechainCount++
+	}
+	agg.WebSearchRequests += e.WebSearchRequests
+	agg.WebFetchRequests += e.WebFetchRequests
+}
+
+// Groups returns the aggregates sorted by cost descending (ties broken by
+// key).
+func (a *GroupAccumulator) Groups() []GroupAggregate {
+	result := make([]GroupAggregate, 0, len(a.groups))
+	for _, agg := range a.groups {
+		result = append(result, *agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
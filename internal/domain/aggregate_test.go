@@ -81,13 +81,42 @@ func TestAggregateMonthly_Empty(t *testing.T) {
 }
 
 func TestDailyAggregate_TotalTokens(t *testing.T) {
-	d := DailyAggregate{
+	d := DailyAggregate{Totals: Totals{
 		InputTokens:         100,
 		OutputTokens:        50,
 		CacheCreationTokens: 25,
 		CacheReadTokens:     10,
-	}
+	}}
 	if got := d.TotalTokens(); got != 185 {
 		t.Errorf("TotalTokens() = %d, want 185", got)
 	}
 }
+
+func TestAggregateBy(t *testing.T) {
+	entries := []UsageEntry{
+		{GitBranch: "main", InputTokens: 100, CostUSD: 1.0},
+		{GitBranch: "feature", InputTokens: 50, CostUSD: 3.0, IsSidechain: true, WebSearchRequests: 2},
+		{GitBranch: "main", OutputTokens: 10, CostUSD: 0.5},
+	}
+
+	result := AggregateBy(entries, KeyBranch)
+	if len(result) != 2 {
+		t.Fatalf("got %d groups, want 2", len(result))
+	}
+	// Sorted by cost descending
+	if result[0].Key != "feature" {
+		t.Errorf("first group = %q, want feature", result[0].Key)
+	}
+	if result[0].SidechainCount != 1 || result[0].WebSearchRequests != 2 {
+		t.Errorf("feature sidechain/web search = %d/%d, want 1/2", result[0].SidechainCount, result[0].WebSearchRequests)
+	}
+	main := result[1]
+	if main.EntriesCount != 2 || main.TotalTokens() != 110 || main.TotalCost != 1.5 {
+		t.Errorf("main = %+v, want 2 entries, 110 tokens, $1.5", main)
+	}
+
+	bySidechain := AggregateBy(entries, KeySidechain)
+	if len(bySidechain) != 2 {
+		t.Errorf("got %d sidechain groups, want 2", len(bySidechain))
+	}
+}
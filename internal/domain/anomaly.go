@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// AnomalyMethod selects the center and spread of an anomaly baseline.
+type AnomalyMethod string
+
+const (
+	AnomalyMAD    AnomalyMethod = "mad"    // median + K·MAD, robust to earlier outliers
+	AnomalyStdDev AnomalyMethod = "stddev" // mean + K·σ
+)
+
+// AnomalyKind names what an anomaly was found in.
+type AnomalyKind string
+
+const (
+	AnomalyDay     AnomalyKind = "day"
+	AnomalyBlock   AnomalyKind = "block"
+	AnomalySession AnomalyKind = "session"
+)
+
+// Defaults for zero AnomalyOptions fields. A baseline needs
+// anomalyMinBaseline earlier items before anything is judged against it.
+const (
+	DefaultAnomalyK      = 3.5
+	DefaultAnomalyWindow = 30
+	anomalyMinBaseline   = 7
+	madScale             = 1.4826 // scales MAD to σ for normal data
+)
+
+// AnomalyOptions configures the detector. Each day, block or session is
+// compared by cost with the Window items before it.
+type AnomalyOptions struct {
+	Method AnomalyMethod
+	K      float64 // spreads above the center that make an outlier
+	Window int     // earlier items in the rolling baseline
+}
+
+func (o AnomalyOptions) withDefaults() AnomalyOptions {
+	if o.Method != AnomalyStdDev {
+		o.Method = AnomalyMAD
+	}
+	if o.K <= 0 {
+		o.K = DefaultAnomalyK
+	}
+	if o.Window < anomalyMinBaseline {
+		o.Window = DefaultAnomalyWindow
+	}
+	return o
+}
+
+// Anomaly is a day, block or session whose cost is far above the baseline
+// of those before it.
+type Anomaly struct {
+	Kind      AnomalyKind
+	Key       string    // date, block start (RFC 3339) or session ID
+	Label     string    // project name for sessions
+	Start     time.Time // start of the day, block or session
+	Cost      float64
+	Tokens    int
+	Baseline  float64 // median or mean cost of the window
+	Threshold float64 // cost above which an item is an outlier
+	Score     float64 // spreads above the baseline
+}
+
+// anomalyItem is one day, block or session under test, in time order.
+type anomalyItem struct {
+	key    string
+	label  string
+	start  time.Time
+	cost   float64
+	tokens int
+}
+
+// DetectAnomalies finds the outlying days, blocks and sessions, most
+// recent first. Days are dated in tz; idle gap blocks are skipped.
+func DetectAnomalies(days []DailyAggregate, blocks []SessionBlock, sessions []SessionAggregate, tz *time.Location, opts AnomalyOptions) []Anomaly {
+	out := []Anomaly{}
+	out = append(out, DayAnomalies(days, tz, opts)...)
+	out = append(out, BlockAnomalies(blocks, opts)...)
+	out = append(out, SessionAnomalies(sessions, opts)...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Start.After(out[j].Start) })
+	return out
+}
+
+// DayAnomalies finds the outlying days.
+func DayAnomalies(days []DailyAggregate, tz *time.Location, opts AnomalyOptions) []Anomaly {
+	if tz == nil {
+		tz = time.UTC
+	}
+	items := make([]anomalyItem, 0, len(days))
+	for _, d := range days {
+		start, err := time.ParseInLocation("2006-01-02", d.Date, tz)
+		if err != nil {
+			continue
+		}
+		items = append(items, anomalyItem{key: d.Date, start: start, cost: d.TotalCost, tokens: d.TotalTokens()})
+	}
+	return detect(AnomalyDay, items, opts)
+}
+
+// BlockAnomalies finds the outlying session blocks.
+func BlockAnomalies(blocks []SessionBlock, opts AnomalyOptions) []Anomaly {
+	items := make([]anomalyItem, 0, len(blocks))
+	for _, b := range blocks {
+		if b.IsGap {
+			continue
+		}
+		items = append(items, anomalyItem{
+			key: BlockKey(b), start: b.StartTime,
+			cost: b.TotalCost, tokens: b.TotalTokens,
+		})
+	}
+	return detect(AnomalyBlock, items, opts)
+}
+
+// BlockKey returns the key that identifies a block in anomalies: its
+// start in RFC 3339.
+func BlockKey(b SessionBlock) string {
+	return b.StartTime.UTC().Format(time.RFC3339)
+}
+
+// SessionAnomalies finds the outlying sessions, in order of their first
+// activity.
+func SessionAnomalies(sessions []SessionAggregate, opts AnomalyOptions) []Anomaly {
+	items := make([]anomalyItem, 0, len(sessions))
+	for _, s := range sessions {
+		items = append(items, anomalyItem{
+			key: s.SessionID, label: s.ProjectName, start: s.FirstActivity,
+			cost: s.TotalCost, tokens: s.TotalTokens(),
+		})
+	}
+	return detect(AnomalySession, items, opts)
+}
+
+// detect compares every item with the window before it, once the window
+// holds enough items. Only costs above the threshold count: a quiet day
+// is not worth a warning.
+func detect(kind AnomalyKind, items []anomalyItem, opts AnomalyOptions) []Anomaly {
+	opts = opts.withDefaults()
+	sort.SliceStable(items, func(i, j int) bool { return items[i].start.Before(items[j].start) })
+
+	var out []Anomaly
+	window := make([]float64, 0, opts.Window)
+	for i, it := range items {
+		if i < anomalyMinBaseline {
+			continue
+		}
+		window = window[:0]
+		for _, prev := range items[max(0, i-opts.Window):i] {
+			window = append(window, prev.cost)
+		}
+		center, spread := baseline(window, opts.Method)
+		// A flat baseline has no spread; fall back to a tenth of its level
+		// so that tiny changes are not flagged.
+		spread = math.Max(spread, center/10)
+		if spread == 0 {
+			continue
+		}
+		if threshold := center + opts.K*spread; it.cost > threshold {
+			out = append(out, Anomaly{
+				Kind: kind, Key: it.key, Label: it.label, Start: it.start,
+				Cost: it.cost, Tokens: it.tokens,
+				Baseline: center, Threshold: threshold,
+				Score: (it.cost - center) / spread,
+			})
+		}
+	}
+	return out
+}
+
+// baseline returns the center and spread of xs: the median and scaled MAD,
+// or the mean and standard deviation.
+func baseline(xs []float64, method AnomalyMethod) (center, spread float64) {
+	if method == AnomalyStdDev {
+		for _, x := range xs {
+			center += x
+		}
+		center /= float64(len(xs))
+		for _, x := range xs {
+			spread += (x - center) * (x - center)
+		}
+		return center, math.Sqrt(spread / float64(len(xs)))
+	}
+	center = median(xs)
+	devs := make([]float64, len(xs))
+	for i, x := range xs {
+		devs[i] = math.Abs(x - center)
+	}
+	return center, madScale * median(devs)
+}
+
+func median(xs []float64) float64 {
+	s := append([]float64(nil), xs...)
+	sort.Float64s(s)
+	n := len(s)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// AnomalyKeys returns the anomalies of kind by key.
+func AnomalyKeys(anomalies []Anomaly, kind AnomalyKind) map[string]Anomaly {
+	keys := make(map[string]Anomaly)
+	for _, a := range anomalies {
+		if a.Kind == kind {
+			keys[a.Key] = a
+		}
+	}
+	return keys
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// anomalyDays returns ten days at about $10, then a $60 day and a $12 day.
+func anomalyDays() []DailyAggregate {
+	costs := []float64{9, 10, 11, 10, 9, 10, 11, 10, 9, 11, 60, 12}
+	days := make([]DailyAggregate, len(costs))
+	for i, c := range costs {
+		// Newest first, as AggregateByDay returns them
+		days[len(costs)-1-i] = DailyAggregate{Date: fmt.Sprintf("2026-03-%02d", i+1), Totals: Totals{TotalCost: c}}
+	}
+	return days
+}
+
+func TestDayAnomalies(t *testing.T) {
+	for _, method := range []AnomalyMethod{AnomalyMAD, AnomalyStdDev} {
+		got := DayAnomalies(anomalyDays(), time.UTC, AnomalyOptions{Method: method})
+		if len(got) != 1 {
+			t.Fatalf("%s: anomalies = %+v, want one", method, got)
+		}
+		a := got[0]
+		if a.Kind != AnomalyDay || a.Key != "2026-03-11" || a.Cost != 60 {
+			t.Errorf("%s: anomaly = %+v, want the $60 day", method, a)
+		}
+		if a.Baseline < 9 || a.Baseline > 11 {
+			t.Errorf("%s: Baseline = %f, want about 10", method, a.Baseline)
+		}
+		if a.Score < DefaultAnomalyK {
+			t.Errorf("%s: Score = %f, want at least %f", method, a.Score, DefaultAnomalyK)
+		}
+	}
+}
+
+func TestDayAnomalies_MADIgnoresEarlierOutliers(t *testing.T) {
+	// A second spike right after the first: the median baseline is not
+	// pulled up by the first spike, so both are flagged.
+	days := anomalyDays()
+	days[0].TotalCost = 55 // the day after the $60 day
+	got := DayAnomalies(days, time.UTC, AnomalyOptions{Method: AnomalyMAD})
+	if len(got) != 2 {
+		t.Errorf("anomalies = %+v, want two", got)
+	}
+}
+
+func TestDayAnomalies_ShortHistory(t *testing.T) {
+	days := []DailyAggregate{{Date: "2026-03-02", Totals: Totals{TotalCost: 100}}, {Date: "2026-03-01", Totals: Totals{TotalCost: 1}}}
+	if got := DayAnomalies(days, time.UTC, AnomalyOptions{}); len(got) != 0 {
+		t.Errorf("anomalies = %+v, want none before the baseline fills", got)
+	}
+}
+
+func TestDayAnomalies_FlatBaseline(t *testing.T) {
+	var days []DailyAggregate
+	for i := 1; i <= 10; i++ {
+		days = append(days, DailyAggregate{Date: fmt.Sprintf("2026-03-%02d", i), Totals: Totals{TotalCost: 10}})
+	}
+	// Within a tenth-of-the-level spread of a flat baseline
+	days = append(days, DailyAggregate{Date: "2026-03-11", Totals: Totals{TotalCost: 12}})
+	if got := DayAnomalies(days, time.UTC, AnomalyOptions{}); len(got) != 0 {
+		t.Errorf("anomalies = %+v, want none", got)
+	}
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	var blocks []SessionBlock
+	var sessions []SessionAggregate
+	for i := range 10 {
+		start := base.Add(time.Duration(i) * 6 * time.Hour)
+		cost := 2.0
+		if i == 9 {
+			cost = 40
+		}
+		blocks = append(blocks, SessionBlock{StartTime: start, Totals: Totals{TotalCost: cost}})
+		// An idle gap between every block is never judged
+		blocks = append(blocks, SessionBlock{StartTime: start.Add(5 * time.Hour), IsGap: true})
+		sessions = append(sessions, SessionAggregate{SessionID: fmt.Sprintf("s%d", i), ProjectName: "app", FirstActivity: start, Totals: Totals{TotalCost: cost}})
+	}
+
+	got := DetectAnomalies(anomalyDays(), blocks, sessions, time.UTC, AnomalyOptions{})
+	if len(got) != 3 {
+		t.Fatalf("anomalies = %+v, want a day, a block and a session", got)
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Start.After(got[i-1].Start) {
+			t.Errorf("anomalies not most recent first: %v before %v", got[i-1].Start, got[i].Start)
+		}
+	}
+
+	blockKeys := AnomalyKeys(got, AnomalyBlock)
+	key := base.Add(54 * time.Hour).Format(time.RFC3339)
+	if _, ok := blockKeys[key]; !ok || len(blockKeys) != 1 {
+		t.Errorf("block keys = %v, want only %s", blockKeys, key)
+	}
+	if s := AnomalyKeys(got, AnomalySession)["s9"]; s.Label != "app" {
+		t.Errorf("session anomaly = %+v, want s9 of app", s)
+	}
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 const BlockDuration = 5 * time.Hour
 
@@ -9,21 +12,23 @@ type BlockStatus string
 const (
 	BlockActive BlockStatus = "active"
 	BlockDone   BlockStatus = "done"
+	BlockGap    BlockStatus = "gap" // idle time between two blocks
 )
 
 type SessionBlock struct {
-	StartTime           time.Time
-	EndTime             time.Time // StartTime + 5h
-	Entries             []UsageEntry
-	TotalTokens         int
-	InputTokens         int
-	OutputTokens        int
-	CacheCreationTokens int
-	CacheReadTokens     int
-	TotalCost           float64
-	MessageCount        int
-	Status              BlockStatus
-	Models              map[string]ModelBreakdown
+	StartTime    time.Time
+	EndTime      time.Time    // StartTime + 5h, earlier if CutShort
+	LastActivity time.Time    // timestamp of the last entry
+	Anchored     bool         // StartTime comes from an observed resets_at
+	CutShort     bool         // an observed window began before StartTime + 5h
+	IsGap        bool         // pseudo-block spanning idle time; no usage
+	Entries      []UsageEntry `json:",omitempty"` // empty when built by a BlockAccumulator
+	TotalTokens  int          // Totals.TotalTokens(), kept as a field for JSON output
+	Totals
+	MessageCount int
+	Status       BlockStatus
+	Models       map[string]ModelBreakdown
+	Tools        map[string]ToolUsage `json:",omitempty"`
 }
 
 type ModelBreakdown struct {
@@ -33,51 +38,228 @@ type ModelBreakdown struct {
 	Percentage float64
 }
 
-// BuildBlocks groups entries into fixed 5-hour session blocks.
+// addModel accumulates an entry into the breakdown of its model.
+func addModel(models map[string]ModelBreakdown, e UsageEntry) {
+	mb := models[e.Model]
+	mb.Model = e.Model
+	mb.Tokens += e.TotalTokens()
+	mb.Cost += e.CostUSD
+	models[e.Model] = mb
+}
+
+// BlockOptions controls how entries are split into session blocks.
+type BlockOptions struct {
+	// Anchors are the starts of windows observed from the usage API (see
+	// WindowStart), in any order. A block starting inside an observed
+	// window starts where the window did, and an observed window cuts the
+	// block before it short.
+	Anchors []time.Time
+
+	// Gaps inserts a gap block for the idle time between two blocks.
+	Gaps bool
+}
+
+// WindowStart returns the start of the 5-hour window that resets at
+// resetsAt. The API's resets_at jitters within the hour, so it is rounded
+// to the nearest hour first.
+func WindowStart(resetsAt time.Time) time.Time {
+	return resetsAt.Round(time.Hour).Add(-BlockDuration).UTC()
+}
+
+// anchors returns the hour-aligned anchors, sorted and deduplicated.
+func (o BlockOptions) anchors() []time.Time {
+	if len(o.Anchors) == 0 {
+		return nil
+	}
+	anchors := make([]time.Time, 0, len(o.Anchors))
+	for _, a := range o.Anchors {
+		anchors = append(anchors, a.Truncate(time.Hour).UTC())
+	}
+	sort.Slice(anchors, func(i, j int) bool { return anchors[i].Before(anchors[j]) })
+	n := 0
+	for i, a := range anchors {
+		if i == 0 || !a.Equal(anchors[n-1]) {
+			anchors[n] = a
+			n++
+		}
+	}
+	return anchors[:n]
+}
+
+// boundary decides whether activity at t, which is not before the start of
+// the current block cur (nil if there is none), starts a new block, and
+// where that block starts. Without anchors a block runs for 5 hours from
+// the hour of its first activity. cut reports that an observed window
+// starting at start ends cur early.
+//
+// Every boundary is on the hour, so deciding by the hour of t instead of t
+// itself gives the same blocks.
+func boundary(anchors []time.Time, cur *SessionBlock, t time.Time) (start time.Time, anchored, cut, ok bool) {
+	if cur != nil {
+		// First anchor in (cur.StartTime, t]
+		i := sort.Search(len(anchors), func(i int) bool { return anchors[i].After(cur.StartTime) })
+		if i < len(anchors) && !anchors[i].After(t) && anchors[i].Before(cur.EndTime) {
+			return anchors[i], true, true, true
+		}
+		if t.Before(cur.EndTime) {
+			return time.Time{}, false, false, false
+		}
+	}
+	// Latest anchor at or before t whose window covers t
+	i := sort.Search(len(anchors), func(i int) bool { return anchors[i].After(t) })
+	if i > 0 {
+		a := anchors[i-1]
+		if t.Before(a.Add(BlockDuration)) && (cur == nil || !a.Before(cur.EndTime)) {
+			return a, true, false, true
+		}
+	}
+	return t.Truncate(time.Hour), false, false, true
+}
+
+// BuildBlocks groups entries into 5-hour session blocks.
 // Entries must be sorted by timestamp (ascending).
-func BuildBlocks(entries []UsageEntry) []SessionBlock {
+func BuildBlocks(entries []UsageEntry, opts BlockOptions) []SessionBlock {
 	if len(entries) == 0 {
 		return nil
 	}
 
+	anchors := opts.anchors()
 	var blocks []SessionBlock
 	var current *SessionBlock
 
 	for _, e := range entries {
-		if current == nil || e.Timestamp.After(current.EndTime) || e.Timestamp.Equal(current.EndTime) {
-			// Start new block — truncate start to the hour boundary
+		if start, anchored, cut, ok := boundary(anchors, current, e.Timestamp); ok {
 			if current != nil {
+				if cut {
+					current.EndTime, current.CutShort = start, true
+				}
 				blocks = append(blocks, *current)
 			}
-			startHour := e.Timestamp.Truncate(time.Hour)
 			current = &SessionBlock{
-				StartTime: startHour,
-				EndTime:   startHour.Add(BlockDuration),
+				StartTime: start,
+				EndTime:   start.Add(BlockDuration),
+				Anchored:  anchored,
 				Models:    make(map[string]ModelBreakdown),
 			}
 		}
 
 		current.Entries = append(current.Entries, e)
-		current.TotalTokens += e.TotalTokens()
-		current.InputTokens += e.InputTokens
-		current.OutputTokens += e.OutputTokens
-		current.CacheCreationTokens += e.CacheCreationTokens
-		current.CacheReadTokens += e.CacheReadTokens
-		current.TotalCost += e.CostUSD
-		current.MessageCount++
-
-		mb := current.Models[e.Model]
-		mb.Model = e.Model
-		mb.Tokens += e.TotalTokens()
-		mb.Cost += e.CostUSD
-		current.Models[e.Model] = mb
+		current.add(e)
 	}
 
 	if current != nil {
 		blocks = append(blocks, *current)
 	}
 
-	// Set status
+	return finishBlocks(blocks, opts.Gaps)
+}
+
+// add accumulates an entry's totals into the block.
+func (b *SessionBlock) add(e UsageEntry) {
+	b.TotalTokens += e.TotalTokens()
+	b.Totals.Add(e)
+	b.MessageCount++
+	if e.Timestamp.After(b.LastActivity) {
+		b.LastActivity = e.Timestamp
+	}
+	addModel(b.Models, e)
+
+	b.Tools = addTools(b.Tools, e)
+}
+
+// merge accumulates the totals of another block into b.
+func (b *SessionBlock) merge(o *SessionBlock) {
+	b.TotalTokens += o.TotalTokens
+	b.Merge(o.Totals)
+	b.MessageCount += o.MessageCount
+	if o.LastActivity.After(b.LastActivity) {
+		b.LastActivity = o.LastActivity
+	}
+	for k, om := range o.Models {
+		mb := b.Models[k]
+		mb.Model = om.Model
+		mb.Tokens += om.Tokens
+		mb.Cost += om.Cost
+		b.Models[k] = mb
+	}
+	for k, ot := range o.Tools {
+		if b.Tools == nil {
+			b.Tools = make(map[string]ToolUsage, len(o.Tools))
+		}
+		tu := b.Tools[k]
+		tu.Name = ot.Name
+		tu.Calls += ot.Calls
+		tu.Turns += ot.Turns
+		tu.Tokens += ot.Tokens
+		tu.Cost += ot.Cost
+		b.Tools[k] = tu
+	}
+}
+
+// BlockAccumulator builds the same blocks as BuildBlocks from entries in
+// any order, without keeping the entries. It holds one total per active
+// hour, which is exact because blocks always start on an hour boundary.
+type BlockAccumulator struct {
+	opts  BlockOptions
+	hours map[int64]*SessionBlock // unix start of hour -> totals
+}
+
+// NewBlockAccumulator returns an empty accumulator.
+func NewBlockAccumulator(opts BlockOptions) *BlockAccumulator {
+	return &BlockAccumulator{opts: opts, hours: make(map[int64]*SessionBlock)}
+}
+
+// Add accumulates one entry.
+func (a *BlockAccumulator) Add(e UsageEntry) {
+	hour := e.Timestamp.Truncate(time.Hour).Unix()
+	h, ok := a.hours[hour]
+	if !ok {
+		h = &SessionBlock{Models: make(map[string]ModelBreakdown)}
+		a.hours[hour] = h
+	}
+	h.add(e)
+}
+
+// Blocks returns the session blocks in chronological order. Their Entries
+// are always empty.
+func (a *BlockAccumulator) Blocks() []SessionBlock {
+	if len(a.hours) == 0 {
+		return nil
+	}
+	hours := make([]int64, 0, len(a.hours))
+	for h := range a.hours {
+		hours = append(hours, h)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i] < hours[j] })
+
+	anchors := a.opts.anchors()
+	var blocks []SessionBlock
+	for _, h := range hours {
+		hour := time.Unix(h, 0).UTC()
+		var cur *SessionBlock
+		if len(blocks) > 0 {
+			cur = &blocks[len(blocks)-1]
+		}
+		if start, anchored, cut, ok := boundary(anchors, cur, hour); ok {
+			if cut {
+				cur.EndTime, cur.CutShort = start, true
+			}
+			blocks = append(blocks, SessionBlock{
+				StartTime: start,
+				EndTime:   start.Add(BlockDuration),
+				Anchored:  anchored,
+				Models:    make(map[string]ModelBreakdown),
+			})
+		}
+		blocks[len(blocks)-1].merge(a.hours[h])
+	}
+
+	return finishBlocks(blocks, a.opts.Gaps)
+}
+
+// finishBlocks sets each block's status and model percentages and, if
+// gaps is set, inserts gap blocks between blocks that do not touch.
+func finishBlocks(blocks []SessionBlock, gaps bool) []SessionBlock {
 	now := time.Now().UTC()
 	for i := range blocks {
 		if now.Before(blocks[i].EndTime) && i == len(blocks)-1 {
@@ -94,5 +276,20 @@ func BuildBlocks(entries []UsageEntry) []SessionBlock {
 		}
 	}
 
-	return blocks
+	if !gaps || len(blocks) < 2 {
+		return blocks
+	}
+	withGaps := make([]SessionBlock, 0, 2*len(blocks)-1)
+	for i, b := range blocks {
+		if i > 0 && b.StartTime.After(blocks[i-1].EndTime) {
+			withGaps = append(withGaps, SessionBlock{
+				StartTime: blocks[i-1].EndTime,
+				EndTime:   b.StartTime,
+				IsGap:     true,
+				Status:    BlockGap,
+			})
+		}
+		withGaps = append(withGaps, b)
+	}
+	return withGaps
 }
@@ -16,7 +16,7 @@ func TestBuildBlocks(t *testing.T) {
 		{Timestamp: base.Add(6 * time.Hour), InputTokens: 300, OutputTokens: 150, Model: "opus", MessageID: "m4", RequestID: "r4"},
 	}
 
-	blocks := BuildBlocks(entries)
+	blocks := BuildBlocks(entries, BlockOptions{})
 
 	if len(blocks) != 2 {
 		t.Fatalf("got %d blocks, want 2", len(blocks))
@@ -51,7 +51,7 @@ func TestBuildBlocks(t *testing.T) {
 }
 
 func TestBuildBlocks_Empty(t *testing.T) {
-	blocks := BuildBlocks(nil)
+	blocks := BuildBlocks(nil, BlockOptions{})
 	if blocks != nil {
 		t.Errorf("got %v, want nil", blocks)
 	}
@@ -65,7 +65,7 @@ func TestBuildBlocks_ModelPercentages(t *testing.T) {
 		{Timestamp: base.Add(1 * time.Hour), InputTokens: 25, OutputTokens: 0, Model: "haiku"},
 	}
 
-	blocks := BuildBlocks(entries)
+	blocks := BuildBlocks(entries, BlockOptions{})
 	if len(blocks) != 1 {
 		t.Fatalf("got %d blocks, want 1", len(blocks))
 	}
@@ -79,3 +79,141 @@ func TestBuildBlocks_ModelPercentages(t *testing.T) {
 		t.Errorf("haiku percentage = %f, want 25.0", haiku.Percentage)
 	}
 }
+
+func TestBlockAccumulator_MatchesBuildBlocks(t *testing.T) {
+	base := time.Date(2026, 2, 21, 10, 20, 0, 0, time.UTC)
+	var entries []UsageEntry
+	for i, offset := range []time.Duration{0, 40 * time.Minute, 4*time.Hour + 30*time.Minute, 4*time.Hour + 50*time.Minute, 5 * time.Hour, 9 * time.Hour, 30 * time.Hour} {
+		model := "opus"
+		if i%2 == 1 {
+			model = "haiku"
+		}
+		entries = append(entries, UsageEntry{
+			Timestamp:   base.Add(offset),
+			InputTokens: 100 * (i + 1), OutputTokens: 10, CostUSD: float64(i + 1), Model: model,
+		})
+	}
+	want := BuildBlocks(entries, BlockOptions{})
+
+	// Feed the accumulator out of order.
+	acc := NewBlockAccumulator(BlockOptions{})
+	for i := len(entries) - 1; i >= 0; i-- {
+		acc.Add(entries[i])
+	}
+	got := acc.Blocks()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d blocks, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.StartTime.Equal(w.StartTime) || g.MessageCount != w.MessageCount ||
+			g.TotalTokens != w.TotalTokens || g.TotalCost != w.TotalCost || len(g.Models) != len(w.Models) {
+			t.Errorf("block %d = %v/%d msgs/%d tokens/$%.0f, want %v/%d/%d/$%.0f", i,
+				g.StartTime, g.MessageCount, g.TotalTokens, g.TotalCost,
+				w.StartTime, w.MessageCount, w.TotalTokens, w.TotalCost)
+		}
+		if len(g.Entries) != 0 {
+			t.Errorf("block %d keeps %d entries, want none", i, len(g.Entries))
+		}
+	}
+}
+
+func TestBuildBlocks_Anchors(t *testing.T) {
+	base := time.Date(2026, 2, 21, 10, 20, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: base, InputTokens: 100, Model: "opus"},                    // 10:00 block
+		{Timestamp: base.Add(2 * time.Hour), InputTokens: 100, Model: "opus"}, // 12:20
+		{Timestamp: base.Add(4 * time.Hour), InputTokens: 100, Model: "opus"}, // 14:20, window from 13:00
+		{Timestamp: base.Add(9 * time.Hour), InputTokens: 100, Model: "opus"}, // 19:20, window from 18:40
+	}
+	anchors := []time.Time{
+		time.Date(2026, 2, 21, 13, 0, 0, 0, time.UTC),
+		WindowStart(time.Date(2026, 2, 21, 23, 40, 12, 0, time.UTC)), // rounds to 19:00
+	}
+
+	blocks := BuildBlocks(entries, BlockOptions{Anchors: anchors})
+	if len(blocks) != 3 {
+		t.Fatalf("got %d blocks, want 3", len(blocks))
+	}
+
+	want := []struct {
+		start, end         int // hour of day
+		msgs               int
+		anchored, cutShort bool
+	}{
+		{10, 13, 2, false, true},
+		{13, 18, 1, true, false},
+		{19, 24, 1, true, false},
+	}
+	day := time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC)
+	for i, w := range want {
+		b := blocks[i]
+		if !b.StartTime.Equal(day.Add(time.Duration(w.start)*time.Hour)) || !b.EndTime.Equal(day.Add(time.Duration(w.end)*time.Hour)) {
+			t.Errorf("block %d = %v - %v, want %02d:00 - %02d:00", i, b.StartTime, b.EndTime, w.start, w.end)
+		}
+		if b.MessageCount != w.msgs || b.Anchored != w.anchored || b.CutShort != w.cutShort {
+			t.Errorf("block %d = %d msgs, anchored %v, cut short %v; want %d, %v, %v", i,
+				b.MessageCount, b.Anchored, b.CutShort, w.msgs, w.anchored, w.cutShort)
+		}
+	}
+	if !blocks[0].LastActivity.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("block 0 LastActivity = %v, want %v", blocks[0].LastActivity, base.Add(2*time.Hour))
+	}
+}
+
+func TestBuildBlocks_Gaps(t *testing.T) {
+	base := time.Date(2026, 2, 21, 10, 20, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: base, InputTokens: 100, Model: "opus"},
+		{Timestamp: base.Add(5 * time.Hour), InputTokens: 100, Model: "opus"}, // 15:20, right after the first block
+		{Timestamp: base.Add(12 * time.Hour), InputTokens: 100, Model: "opus"},
+	}
+
+	blocks := BuildBlocks(entries, BlockOptions{Gaps: true})
+	if len(blocks) != 4 {
+		t.Fatalf("got %d blocks, want 4", len(blocks))
+	}
+	gap := blocks[2]
+	if !gap.IsGap || gap.Status != BlockGap || gap.MessageCount != 0 {
+		t.Errorf("blocks[2] = %+v, want an empty gap", gap)
+	}
+	if !gap.StartTime.Equal(blocks[1].EndTime) || !gap.EndTime.Equal(blocks[3].StartTime) {
+		t.Errorf("gap = %v - %v, want %v - %v", gap.StartTime, gap.EndTime, blocks[1].EndTime, blocks[3].StartTime)
+	}
+	for _, i := range []int{0, 1, 3} {
+		if blocks[i].IsGap {
+			t.Errorf("blocks[%d] is a gap", i)
+		}
+	}
+}
+
+func TestBlockAccumulator_MatchesBuildBlocksWithAnchors(t *testing.T) {
+	base := time.Date(2026, 2, 21, 10, 20, 0, 0, time.UTC)
+	var entries []UsageEntry
+	for i, offset := range []time.Duration{0, 2 * time.Hour, 3*time.Hour + 10*time.Minute, 4 * time.Hour, 11 * time.Hour, 30 * time.Hour} {
+		entries = append(entries, UsageEntry{Timestamp: base.Add(offset), InputTokens: 100 * (i + 1), Model: "opus"})
+	}
+	opts := BlockOptions{
+		Anchors: []time.Time{base.Add(3 * time.Hour).Truncate(time.Hour), base.Add(9 * time.Hour).Truncate(time.Hour)},
+		Gaps:    true,
+	}
+	want := BuildBlocks(entries, opts)
+
+	acc := NewBlockAccumulator(opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	got := acc.Blocks()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d blocks, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if !g.StartTime.Equal(w.StartTime) || !g.EndTime.Equal(w.EndTime) || g.MessageCount != w.MessageCount ||
+			g.Anchored != w.Anchored || g.CutShort != w.CutShort || g.IsGap != w.IsGap {
+			t.Errorf("block %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
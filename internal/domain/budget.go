@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BudgetPeriod is the period a budget limit resets with.
+type BudgetPeriod string
+
+const (
+	BudgetDay   BudgetPeriod = "day"   // calendar day
+	BudgetWeek  BudgetPeriod = "week"  // Monday to Sunday
+	BudgetMonth BudgetPeriod = "month" // billing cycle
+)
+
+// BudgetThresholds are the shares of a limit that raise an alert.
+var BudgetThresholds = []float64{0.5, 0.8, 1}
+
+// Budget limits the API-equivalent cost and the tokens of one period,
+// optionally for a single project or model.
+type Budget struct {
+	Period  BudgetPeriod
+	Cost    float64 // USD; 0 = no cost limit
+	Tokens  int     // 0 = no token limit
+	Project string  `json:",omitempty"` // project name or root; "" = all
+	Model   string  `json:",omitempty"` // part of the model name; "" = all
+}
+
+// ParseBudgetPeriod accepts day, week and month along with daily, weekly
+// and monthly.
+func ParseBudgetPeriod(s string) (BudgetPeriod, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "day", "daily":
+		return BudgetDay, nil
+	case "week", "weekly":
+		return BudgetWeek, nil
+	case "month", "monthly":
+		return BudgetMonth, nil
+	}
+	return "", fmt.Errorf("unknown budget period %q (use day, week or month)", s)
+}
+
+// Name describes the budget, e.g. "month · mono · opus".
+func (b Budget) Name() string {
+	parts := []string{string(b.Period)}
+	if b.Project != "" {
+		parts = append(parts, b.Project)
+	}
+	if b.Model != "" {
+		parts = append(parts, b.Model)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// Match reports whether the budget counts an entry.
+func (b Budget) Match(e UsageEntry) bool {
+	if b.Project != "" && !strings.EqualFold(b.Project, e.ProjectName) && b.Project != e.ProjectPath {
+		return false
+	}
+	return b.Model == "" || strings.Contains(strings.ToLower(e.Model), strings.ToLower(b.Model))
+}
+
+// BudgetStatus is the progress of a budget through its current period.
+type BudgetStatus struct {
+	Budget
+	Start   time.Time
+	End     time.Time
+	Spent   float64 // USD
+	Used    int     // tokens
+	Share   float64 // the larger of Spent/Cost and Used/Tokens
+	Crossed float64 // highest threshold reached, 0 if none
+}
+
+// CostShare returns Spent as a share of the cost limit, 0 without one.
+func (s BudgetStatus) CostShare() float64 {
+	if s.Cost <= 0 {
+		return 0
+	}
+	return s.Spent / s.Cost
+}
+
+// TokenShare returns Used as a share of the token limit, 0 without one.
+func (s BudgetStatus) TokenShare() float64 {
+	if s.Tokens <= 0 {
+		return 0
+	}
+	return float64(s.Used) / float64(s.Tokens)
+}
+
+// BudgetOptions places the budget periods.
+type BudgetOptions struct {
+	Now        time.Time
+	Location   *time.Location
+	BillingDay int // day of the month a billing cycle starts; 0 = 1
+}
+
+// TrackBudgets returns the progress of each budget, in order.
+func TrackBudgets(entries []UsageEntry, budgets []Budget, opts BudgetOptions) []BudgetStatus {
+	acc := NewBudgetAccumulator(budgets, opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Statuses()
+}
+
+// BudgetAccumulator tracks budgets one entry at a time.
+type BudgetAccumulator struct {
+	statuses []BudgetStatus
+	totals   []Totals // usage within each budget's period
+}
+
+// NewBudgetAccumulator returns an accumulator for the periods of the
+// budgets that contain opts.Now.
+func NewBudgetAccumulator(budgets []Budget, opts BudgetOptions) *BudgetAccumulator {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	now := opts.Now.In(opts.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, opts.Location)
+	a := &BudgetAccumulator{statuses: make([]BudgetStatus, len(budgets)), totals: make([]Totals, len(budgets))}
+	for i, b := range budgets {
+		s := BudgetStatus{Budget: b}
+		switch b.Period {
+		case BudgetDay:
+			s.Start, s.End = today, today.AddDate(0, 0, 1)
+		case BudgetWeek:
+			s.Start = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+			s.End = s.Start.AddDate(0, 0, 7)
+		default:
+			s.Start, s.End = BillingCycle(now, opts.BillingDay)
+		}
+		a.statuses[i] = s
+	}
+	return a
+}
+
+// Add accumulates one entry.
+func (a *BudgetAccumulator) Add(e UsageEntry) {
+	for i := range a.statuses {
+		s := &a.statuses[i]
+		if e.Timestamp.Before(s.Start) || !e.Timestamp.Before(s.End) || !s.Match(e) {
+			continue
+		}
+		a.totals[i].Add(e)
+	}
+}
+
+// Statuses returns the progress of each budget.
+func (a *BudgetAccumulator) Statuses() []BudgetStatus {
+	out := make([]BudgetStatus, len(a.statuses))
+	for i, s := range a.statuses {
+		s.Spent, s.Used = a.totals[i].TotalCost, a.totals[i].TotalTokens()
+		s.Share = max(s.CostShare(), s.TokenShare())
+		for _, t := range BudgetThresholds {
+			if s.Share >= t {
+				s.Crossed = t
+			}
+		}
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseBudgetPeriod(t *testing.T) {
+	for in, want := range map[string]BudgetPeriod{"day": BudgetDay, "Weekly": BudgetWeek, " month ": BudgetMonth} {
+		if got, err := ParseBudgetPeriod(in); err != nil || got != want {
+			t.Errorf("ParseBudgetPeriod(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseBudgetPeriod("year"); err == nil {
+		t.Error("ParseBudgetPeriod(year) succeeded, want error")
+	}
+}
+
+func TestTrackBudgets(t *testing.T) {
+	// Wednesday 2026-03-11 12:00 UTC; the billing cycle starts on the 5th
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: now.Add(-time.Hour), Model: "claude-opus-4-6", ProjectName: "mono", InputTokens: 600, CostUSD: 6},
+		{Timestamp: now.Add(-2 * time.Hour), Model: "claude-sonnet-4-6", ProjectName: "web", InputTokens: 400, CostUSD: 2},
+		{Timestamp: time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC), Model: "claude-opus-4-6", ProjectName: "mono", CostUSD: 10}, // Monday
+		{Timestamp: time.Date(2026, 3, 6, 8, 0, 0, 0, time.UTC), Model: "claude-opus-4-6", ProjectName: "mono", CostUSD: 20},
+		{Timestamp: time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC), Model: "claude-opus-4-6", ProjectName: "mono", CostUSD: 99}, // last cycle
+	}
+	budgets := []Budget{
+		{Period: BudgetDay, Cost: 10, Tokens: 2000},
+		{Period: BudgetWeek, Cost: 20},
+		{Period: BudgetMonth, Cost: 100, Project: "MONO", Model: "opus"},
+		{Period: BudgetDay, Tokens: 500, Model: "sonnet"},
+	}
+	got := TrackBudgets(entries, budgets, BudgetOptions{Now: now, Location: time.UTC, BillingDay: 5})
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %f, want %f", name, got, want)
+		}
+	}
+	day, week, month, sonnet := got[0], got[1], got[2], got[3]
+	approx("day spent", day.Spent, 8)
+	approx("day share", day.Share, 0.8) // cost share beats the token share of 0.5
+	approx("day crossed", day.Crossed, 0.8)
+	approx("week spent", week.Spent, 18)
+	approx("week crossed", week.Crossed, 0.8)
+	if want := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC); !week.Start.Equal(want) {
+		t.Errorf("week start = %v, want %v", week.Start, want)
+	}
+	approx("month spent", month.Spent, 36)
+	approx("month crossed", month.Crossed, 0)
+	if want := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC); !month.Start.Equal(want) {
+		t.Errorf("month start = %v, want %v", month.Start, want)
+	}
+	if sonnet.Used != 400 || sonnet.Crossed != 0.8 {
+		t.Errorf("sonnet = %d tokens, crossed %v, want 400 and 0.8", sonnet.Used, sonnet.Crossed)
+	}
+	if name := month.Name(); name != "month · MONO · opus" {
+		t.Errorf("Name = %q", name)
+	}
+}
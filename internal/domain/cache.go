@@ -0,0 +1,323 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Prompt cache lifetimes. A cache write without a recorded lifetime is
+// taken to be a 5-minute write, the default.
+const (
+	CacheTTL5m = 5 * time.Minute
+	CacheTTL1h = time.Hour
+)
+
+// CacheRates are a model's prices in USD per million tokens of uncached
+// input, cache writes and cache reads.
+type CacheRates struct {
+	Input   float64
+	Write5m float64
+	Write1h float64
+	Read    float64
+}
+
+// CacheRateFunc returns the cache rates of a model, or false if the model
+// has no known price.
+type CacheRateFunc func(model string) (CacheRates, bool)
+
+// CacheStats measures how well prompt caching paid off for the entries
+// sharing a key, such as a session, block, project or day.
+type CacheStats struct {
+	Key                 string
+	Start               time.Time `json:",omitzero"`  // blocks and days
+	Project             string    `json:",omitempty"` // sessions only
+	Messages            int
+	InputTokens         int // uncached input
+	CacheCreationTokens int
+	CacheReadTokens     int
+	WastedTokens        int     // cache writes never read before they expired
+	ReadRatio           float64 // CacheReadTokens / CacheCreationTokens
+	HitRate             float64 // share of the prompt tokens read from the cache
+	Savings             float64 // USD saved by reads over the input rate
+	Premium             float64 // USD paid for writes over the input rate
+	Net                 float64 // Savings - Premium; negative when caching cost more
+	WastedCost          float64 // the part of Premium paid for WastedTokens
+}
+
+// CacheReport holds cache efficiency in total and per session, block,
+// project and day.
+type CacheReport struct {
+	Total    CacheStats
+	Sessions []CacheStats // most cache tokens first
+	Blocks   []CacheStats // newest first
+	Projects []CacheStats // most cache tokens first
+	Days     []CacheStats // newest first
+}
+
+// CacheOptions controls how a cache report is built.
+type CacheOptions struct {
+	Location *time.Location // of the days
+	Rates    CacheRateFunc  // nil leaves the USD figures at 0
+	Blocks   BlockOptions   // Gaps is ignored
+	Now      time.Time      // writes younger than their TTL are not wasted yet
+}
+
+// BuildCacheReport builds a cache report from entries.
+func BuildCacheReport(entries []UsageEntry, opts CacheOptions) CacheReport {
+	acc := NewCacheAccumulator(opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Report()
+}
+
+// CacheAccumulator builds a cache report one entry at a time. Besides one
+// total per group it keeps a small event per entry that touches the
+// cache, since whether a write was wasted depends on the reads after it.
+type CacheAccumulator struct {
+	opts     CacheOptions
+	blocks   *BlockAccumulator
+	total    cacheTotals
+	sessions map[string]*cacheTotals
+	projects map[string]*cacheTotals
+	days     map[string]*cacheTotals
+	hours    map[int64]*cacheTotals // by unix start of hour, merged into blocks
+	streams  map[cacheStream][]cacheEvent
+}
+
+type cacheTotals struct {
+	project string
+	Totals
+	messages, wasted             int
+	savings, premium, wastedCost float64
+}
+
+// cacheStream identifies the entries that can read each other's cache
+// writes: the cache is per model, and subagents cache their own prompts.
+type cacheStream struct {
+	session   string
+	model     string
+	sidechain bool
+}
+
+type cacheEvent struct {
+	at                   time.Time
+	read                 bool
+	write5m, write1h     int
+	premium5m, premium1h float64 // USD per token
+	project, day         string
+	hour                 int64
+}
+
+// NewCacheAccumulator returns an empty accumulator.
+func NewCacheAccumulator(opts CacheOptions) *CacheAccumulator {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	opts.Blocks.Gaps = false
+	return &CacheAccumulator{
+		opts:     opts,
+		blocks:   NewBlockAccumulator(opts.Blocks),
+		sessions: make(map[string]*cacheTotals),
+		projects: make(map[string]*cacheTotals),
+		days:     make(map[string]*cacheTotals),
+		hours:    make(map[int64]*cacheTotals),
+		streams:  make(map[cacheStream][]cacheEvent),
+	}
+}
+
+// Add accumulates one entry.
+func (a *CacheAccumulator) Add(e UsageEntry) {
+	a.blocks.Add(e)
+
+	// Writes not recorded as 1-hour writes count as 5-minute writes
+	write1h := min(e.CacheCreation1hTokens, e.CacheCreationTokens)
+	ev := cacheEvent{
+		at:      e.Timestamp,
+		read:    e.CacheReadTokens > 0,
+		write5m: e.CacheCreationTokens - write1h,
+		write1h: write1h,
+		project: KeyProject(e),
+		day:     e.Timestamp.In(a.opts.Location).Format("2006-01-02"),
+		hour:    e.Timestamp.Truncate(time.Hour).Unix(),
+	}
+	var savings float64
+	if a.opts.Rates != nil {
+		if r, ok := a.opts.Rates(e.Model); ok {
+			savings = float64(e.CacheReadTokens) * (r.Input - r.Read) / 1_000_000
+			ev.premium5m = (r.Write5m - r.Input) / 1_000_000
+			ev.premium1h = (r.Write1h - r.Input) / 1_000_000
+		}
+	}
+	premium := float64(ev.write5m)*ev.premium5m + float64(ev.write1h)*ev.premium1h
+
+	session := a.group(a.sessions, e.SessionID)
+	session.project = ev.project
+	for _, t := range []*cacheTotals{&a.total, session, a.group(a.projects, ev.project), a.group(a.days, ev.day), a.hour(ev.hour)} {
+		t.messages++
+		t.Add(e)
+		t.savings += savings
+		t.premium += premium
+	}
+
+	if e.CacheCreationTokens > 0 || e.CacheReadTokens > 0 {
+		s := cacheStream{session: e.SessionID, model: e.Model, sidechain: e.IsSidechain}
+		a.streams[s] = append(a.streams[s], ev)
+	}
+}
+
+func (a *CacheAccumulator) group(m map[string]*cacheTotals, key string) *cacheTotals {
+	t, ok := m[key]
+	if !ok {
+		t = &cacheTotals{}
+		m[key] = t
+	}
+	return t
+}
+
+func (a *CacheAccumulator) hour(h int64) *cacheTotals {
+	t, ok := a.hours[h]
+	if !ok {
+		t = &cacheTotals{}
+		a.hours[h] = t
+	}
+	return t
+}
+
+// Report returns the cache report. A write is wasted if no later entry of
+// its session, model and thread read the cache within the write's TTL;
+// writes whose TTL has not run out by Now are not counted yet.
+func (a *CacheAccumulator) Report() CacheReport {
+	a.resetWaste()
+	for s, events := range a.streams {
+		sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+		var nextRead time.Time
+		for i := len(events) - 1; i >= 0; i-- {
+			ev := events[i]
+			var tokens int
+			var cost float64
+			for _, w := range []struct {
+				tokens  int
+				premium float64
+				ttl     time.Duration
+			}{{ev.write5m, ev.premium5m, CacheTTL5m}, {ev.write1h, ev.premium1h, CacheTTL1h}} {
+				if w.tokens == 0 {
+					continue
+				}
+				if nextRead.IsZero() && a.opts.Now.Sub(ev.at) <= w.ttl {
+					continue // may still be read
+				}
+				if nextRead.IsZero() || nextRead.Sub(ev.at) > w.ttl {
+					tokens += w.tokens
+					cost += float64(w.tokens) * w.premium
+				}
+			}
+			if ev.read {
+				nextRead = ev.at
+			}
+			if tokens == 0 {
+				continue
+			}
+			for _, t := range []*cacheTotals{&a.total, a.sessions[s.session], a.projects[ev.project], a.days[ev.day], a.hours[ev.hour]} {
+				t.wasted += tokens
+				t.wastedCost += cost
+			}
+		}
+	}
+
+	r := CacheReport{Total: a.total.stats("all")}
+	for k, t := range a.sessions {
+		s := t.stats(k)
+		s.Project = t.project
+		r.Sessions = append(r.Sessions, s)
+	}
+	for k, t := range a.projects {
+		r.Projects = append(r.Projects, t.stats(k))
+	}
+	for k, t := range a.days {
+		s := t.stats(k)
+		s.Start, _ = time.ParseInLocation("2006-01-02", k, a.opts.Location)
+		r.Days = append(r.Days, s)
+	}
+	r.Blocks = a.blockStats()
+
+	byTokens := func(s []CacheStats) {
+		sort.Slice(s, func(i, j int) bool {
+			ti := s[i].CacheCreationTokens + s[i].CacheReadTokens
+			tj := s[j].CacheCreationTokens + s[j].CacheReadTokens
+			if ti != tj {
+				return ti > tj
+			}
+			return s[i].Key < s[j].Key
+		})
+	}
+	byTokens(r.Sessions)
+	byTokens(r.Projects)
+	sort.Slice(r.Days, func(i, j int) bool { return r.Days[i].Start.After(r.Days[j].Start) })
+	return r
+}
+
+// resetWaste clears the waste of every total, so Report can be called
+// again after more entries.
+func (a *CacheAccumulator) resetWaste() {
+	a.total.wasted, a.total.wastedCost = 0, 0
+	for _, m := range []map[string]*cacheTotals{a.sessions, a.projects, a.days} {
+		for _, t := range m {
+			t.wasted, t.wastedCost = 0, 0
+		}
+	}
+	for _, t := range a.hours {
+		t.wasted, t.wastedCost = 0, 0
+	}
+}
+
+// blockStats merges the hourly totals into the session blocks, newest
+// first. Blocks start on an hour, so every hour falls in one block.
+func (a *CacheAccumulator) blockStats() []CacheStats {
+	blocks := a.blocks.Blocks()
+	totals := make([]cacheTotals, len(blocks))
+	for h, t := range a.hours {
+		at := time.Unix(h, 0)
+		i := sort.Search(len(blocks), func(i int) bool { return blocks[i].EndTime.After(at) })
+		if i == len(blocks) || at.Before(blocks[i].StartTime) {
+			continue
+		}
+		b := &totals[i]
+		b.messages += t.messages
+		b.Merge(t.Totals)
+		b.wasted += t.wasted
+		b.savings += t.savings
+		b.premium += t.premium
+		b.wastedCost += t.wastedCost
+	}
+	stats := make([]CacheStats, 0, len(blocks))
+	for i := len(blocks) - 1; i >= 0; i-- {
+		s := totals[i].stats(BlockKey(blocks[i]))
+		s.Start = blocks[i].StartTime
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+func (t cacheTotals) stats(key string) CacheStats {
+	s := CacheStats{
+		Key:                 key,
+		Messages:            t.messages,
+		InputTokens:         t.InputTokens,
+		CacheCreationTokens: t.CacheCreationTokens,
+		CacheReadTokens:     t.CacheReadTokens,
+		WastedTokens:        t.wasted,
+		HitRate:             t.CacheHitRatio(),
+		Savings:             t.savings,
+		Premium:             t.premium,
+		Net:                 t.savings - t.premium,
+		WastedCost:          t.wastedCost,
+	}
+	if t.CacheCreationTokens > 0 {
+		s.ReadRatio = float64(t.CacheReadTokens) / float64(t.CacheCreationTokens)
+	}
+	return s
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildCacheReport(t *testing.T) {
+	t0 := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	rates := func(model string) (CacheRates, bool) {
+		return CacheRates{Input: 3, Write5m: 3.75, Write1h: 6, Read: 0.3}, model == "claude-sonnet-4-6"
+	}
+	const model = "claude-sonnet-4-6"
+	entries := []UsageEntry{
+		// Written, read two minutes later, then written again and never read
+		{Timestamp: t0, SessionID: "a", ProjectName: "api", Model: model, InputTokens: 100, CacheCreationTokens: 1_000_000},
+		{Timestamp: t0.Add(2 * time.Minute), SessionID: "a", ProjectName: "api", Model: model, CacheReadTokens: 1_000_000, CacheCreationTokens: 200_000},
+		// A 1-hour write read after 30 minutes, by the same model only
+		{Timestamp: t0, SessionID: "b", ProjectName: "web", Model: model, CacheCreationTokens: 400_000, CacheCreation1hTokens: 400_000},
+		{Timestamp: t0.Add(10 * time.Minute), SessionID: "b", ProjectName: "web", Model: "claude-haiku-4-5", CacheReadTokens: 50},
+		{Timestamp: t0.Add(30 * time.Minute), SessionID: "b", ProjectName: "web", Model: model, CacheReadTokens: 400_000},
+		// Written a minute before now: may still be read
+		{Timestamp: t0.Add(10 * time.Hour), SessionID: "c", ProjectName: "web", Model: model, CacheCreationTokens: 500},
+	}
+	r := BuildCacheReport(entries, CacheOptions{Location: time.UTC, Rates: rates, Now: t0.Add(10*time.Hour + time.Minute)})
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s = %f, want %f", name, got, want)
+		}
+	}
+	if len(r.Sessions) != 3 || r.Sessions[0].Key != "a" || r.Sessions[0].Project != "api" {
+		t.Fatalf("Sessions = %+v, want a (api) first", r.Sessions)
+	}
+	a := r.Sessions[0]
+	if a.WastedTokens != 200_000 {
+		t.Errorf("session a wasted = %d, want 200000", a.WastedTokens)
+	}
+	approx("session a read ratio", a.ReadRatio, 1_000_000.0/1_200_000)
+	approx("session a hit rate", a.HitRate, 1_000_000.0/2_200_100)
+	approx("session a savings", a.Savings, 2.7)
+	approx("session a premium", a.Premium, 0.9)
+	approx("session a net", a.Net, 1.8)
+	approx("session a wasted cost", a.WastedCost, 0.15)
+
+	b := r.Sessions[1]
+	if b.Key != "b" || b.WastedTokens != 0 {
+		t.Errorf("session b = %+v, want nothing wasted", b)
+	}
+	approx("session b premium", b.Premium, 1.2) // 1-hour rate
+
+	if r.Total.WastedTokens != 200_000 || r.Total.Messages != 6 {
+		t.Errorf("Total = %+v, want 200000 wasted over 6 messages", r.Total)
+	}
+	if len(r.Projects) != 2 || r.Projects[0].Key != "api" || r.Projects[0].WastedTokens != 200_000 {
+		t.Errorf("Projects = %+v, want api with the waste first", r.Projects)
+	}
+	if len(r.Days) != 1 || !r.Days[0].Start.Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Days = %+v, want Mar 10", r.Days)
+	}
+	if len(r.Blocks) != 2 || !r.Blocks[1].Start.Equal(t0) || r.Blocks[1].WastedTokens != 200_000 || r.Blocks[0].Messages != 1 {
+		t.Errorf("Blocks = %+v, want the 09:00 block with the waste last", r.Blocks)
+	}
+}
@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The usage API reports only how much of the 5-hour window is used, as a
+// percentage. Pairing those percentages with the tokens logged locally in
+// the same window estimates the cap behind them. Each model family
+// appears to draw on the cap at its own rate, so samples are grouped by
+// the mix of families that used the window.
+const (
+	minCapSamples = 3   // samples an estimate needs
+	mixShare      = 0.1 // share of tokens that puts a family in the mix
+)
+
+// CapSample pairs a 5-hour utilization reported by the usage API with the
+// usage logged locally in the same window up to that moment.
+type CapSample struct {
+	Time        time.Time // when the utilization was reported
+	Window      time.Time // start of the 5-hour window
+	Utilization float64   // percent
+	Tokens      int       // logged in the window up to Time
+	Cost        float64   // API-equivalent USD of those tokens
+	Mix         string    // model families with a tenth of the tokens, e.g. "opus+sonnet"
+}
+
+// NewCapSample sums the entries logged in the window starting at window
+// up to at.
+func NewCapSample(entries []UsageEntry, window, at time.Time, utilization float64) CapSample {
+	s := CapSample{Time: at, Window: window, Utilization: utilization}
+	families := make(map[string]int)
+	for _, e := range entries {
+		if e.Timestamp.Before(window) || e.Timestamp.After(at) {
+			continue
+		}
+		tokens := e.TotalTokens()
+		s.Tokens += tokens
+		s.Cost += e.CostUSD
+		families[ModelFamily(e.Model)] += tokens
+	}
+	var mix []string
+	for f, tokens := range families {
+		if float64(tokens) >= mixShare*float64(s.Tokens) && tokens > 0 {
+			mix = append(mix, f)
+		}
+	}
+	sort.Strings(mix)
+	s.Mix = strings.Join(mix, "+")
+	return s
+}
+
+// ModelFamily returns opus, sonnet or haiku for Claude models, and the
+// model name otherwise.
+func ModelFamily(model string) string {
+	lower := strings.ToLower(model)
+	for _, f := range []string{"opus", "sonnet", "haiku"} {
+		if strings.Contains(lower, f) {
+			return f
+		}
+	}
+	return model
+}
+
+// CapEstimate is the 5-hour cap implied by the samples of one model mix.
+type CapEstimate struct {
+	Mix     string // "" for all samples together
+	Samples int
+	Tokens  float64 // tokens at 100%
+	Cost    float64 // API-equivalent USD at 100%
+	Fit     float64 // R² of utilization against tokens, 0-1
+}
+
+// TokensLeft returns the tokens left before the cap at utilization.
+func (c CapEstimate) TokensLeft(utilization float64) float64 {
+	return math.Max(c.Tokens*(100-utilization)/100, 0)
+}
+
+// EstimateCaps fits utilization as a proportion of tokens, and of cost,
+// for each model mix with enough samples, most sampled first, followed by
+// the fit over all samples. Samples without usage or utilization say
+// nothing about the cap and are skipped.
+func EstimateCaps(samples []CapSample) []CapEstimate {
+	byMix := make(map[string][]CapSample)
+	var all []CapSample
+	for _, s := range samples {
+		if s.Tokens <= 0 || s.Utilization <= 0 {
+			continue
+		}
+		byMix[s.Mix] = append(byMix[s.Mix], s)
+		all = append(all, s)
+	}
+	var out []CapEstimate
+	for mix, group := range byMix {
+		if c, ok := fitCap(group); ok {
+			c.Mix = mix
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Samples != out[j].Samples {
+			return out[i].Samples > out[j].Samples
+		}
+		return out[i].Mix < out[j].Mix
+	})
+	if c, ok := fitCap(all); ok && len(byMix) > 1 {
+		out = append(out, c)
+	}
+	return out
+}
+
+// CapFor returns the estimate for mix, or the one over all samples when
+// mix has none.
+func CapFor(estimates []CapEstimate, mix string) (CapEstimate, bool) {
+	var overall *CapEstimate
+	for i, c := range estimates {
+		if c.Mix == mix {
+			return c, true
+		}
+		if c.Mix == "" {
+			overall = &estimates[i]
+		}
+	}
+	if overall == nil && len(estimates) == 1 {
+		// A single mix is also the overall estimate
+		overall = &estimates[0]
+	}
+	if overall == nil {
+		return CapEstimate{}, false
+	}
+	return *overall, true
+}
+
+// fitCap fits utilization = 100·x/cap through the origin by least
+// squares, for tokens and for cost. Larger samples weigh more, which
+// suits utilization reported in whole percents.
+func fitCap(samples []CapSample) (CapEstimate, bool) {
+	if len(samples) < minCapSamples {
+		return CapEstimate{}, false
+	}
+	var sxy, sxx, scy, scc, syy, sy float64
+	for _, s := range samples {
+		x, c, y := float64(s.Tokens), s.Cost, s.Utilization/100
+		sxy += x * y
+		sxx += x * x
+		scy += c * y
+		scc += c * c
+		sy += y
+		syy += y * y
+	}
+	if sxy <= 0 || scy <= 0 {
+		return CapEstimate{}, false
+	}
+	slope := sxy / sxx
+	est := CapEstimate{Samples: len(samples), Tokens: 1 / slope, Cost: scc / scy}
+
+	// R² about the mean of the utilizations
+	n := float64(len(samples))
+	var ssRes float64
+	for _, s := range samples {
+		r := s.Utilization/100 - slope*float64(s.Tokens)
+		ssRes += r * r
+	}
+	if ssTot := syy - sy*sy/n; ssTot > 0 {
+		est.Fit = math.Max(1-ssRes/ssTot, 0)
+	}
+	return est, true
+}
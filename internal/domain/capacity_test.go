@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestNewCapSample(t *testing.T) {
+	window := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: window.Add(-time.Minute), Model: "claude-opus-4-6", InputTokens: 999}, // before the window
+		{Timestamp: window.Add(time.Hour), Model: "claude-opus-4-6", InputTokens: 900, CostUSD: 2},
+		{Timestamp: window.Add(2 * time.Hour), Model: "claude-sonnet-4-6", InputTokens: 50, CostUSD: 0.1},
+		{Timestamp: window.Add(2 * time.Hour), Model: "claude-haiku-4-5", InputTokens: 150, CostUSD: 0.1},
+		{Timestamp: window.Add(4 * time.Hour), Model: "claude-opus-4-6", InputTokens: 999}, // after the sample
+	}
+	s := NewCapSample(entries, window, window.Add(3*time.Hour), 40)
+	if s.Tokens != 1100 || math.Abs(s.Cost-2.2) > 1e-9 {
+		t.Errorf("Tokens, Cost = %d, %f, want 1100, 2.2", s.Tokens, s.Cost)
+	}
+	// Sonnet has under a tenth of the tokens
+	if s.Mix != "haiku+opus" {
+		t.Errorf("Mix = %q, want haiku+opus", s.Mix)
+	}
+}
+
+func TestEstimateCaps(t *testing.T) {
+	var samples []CapSample
+	// Opus windows: 1M tokens ($100) fill the window
+	for _, u := range []float64{10, 25, 50, 80} {
+		samples = append(samples, CapSample{Utilization: u, Tokens: int(u * 10_000), Cost: u, Mix: "opus"})
+	}
+	// Sonnet windows: 4M tokens ($60) fill it
+	for _, u := range []float64{20, 60, 90} {
+		samples = append(samples, CapSample{Utilization: u, Tokens: int(u * 40_000), Cost: u * 0.6, Mix: "sonnet"})
+	}
+	// Nothing logged locally
+	samples = append(samples, CapSample{Utilization: 30, Mix: "opus"})
+
+	caps := EstimateCaps(samples)
+	if len(caps) != 3 {
+		t.Fatalf("EstimateCaps = %+v, want opus, sonnet and overall", caps)
+	}
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > want*1e-6 {
+			t.Errorf("%s = %f, want %f", name, got, want)
+		}
+	}
+	opus, sonnet := caps[0], caps[1]
+	if opus.Mix != "opus" || opus.Samples != 4 || sonnet.Mix != "sonnet" || caps[2].Mix != "" {
+		t.Fatalf("EstimateCaps order = %+v", caps)
+	}
+	approx("opus tokens", opus.Tokens, 1_000_000)
+	approx("opus cost", opus.Cost, 100)
+	approx("opus fit", opus.Fit, 1)
+	approx("sonnet tokens", sonnet.Tokens, 4_000_000)
+	approx("sonnet cost", sonnet.Cost, 60)
+	approx("tokens left", opus.TokensLeft(75), 250_000)
+
+	if c, ok := CapFor(caps, "sonnet"); !ok || c.Mix != "sonnet" {
+		t.Errorf("CapFor(sonnet) = %+v, %v", c, ok)
+	}
+	if c, ok := CapFor(caps, "haiku"); !ok || c.Mix != "" {
+		t.Errorf("CapFor(haiku) = %+v, %v, want the overall estimate", c, ok)
+	}
+}
+
+func TestEstimateCaps_TooFewSamples(t *testing.T) {
+	samples := []CapSample{{Utilization: 10, Tokens: 100, Cost: 1}, {Utilization: 20, Tokens: 200, Cost: 2}}
+	if caps := EstimateCaps(samples); len(caps) != 0 {
+		t.Errorf("EstimateCaps = %+v, want none", caps)
+	}
+	if _, ok := CapFor(nil, "opus"); ok {
+		t.Error("CapFor(nil) ok = true, want false")
+	}
+}
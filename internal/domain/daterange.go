@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RangeContext resolves the bounds of a date range: dates, timestamps,
+// durations back from now such as 7d or 24h, and named periods such as
+// today or last-month. Everything resolves in Location.
+type RangeContext struct {
+	Now        time.Time
+	Location   *time.Location
+	BillingDay int // day of the month a billing cycle starts; 0 = 1
+}
+
+// RangePeriods lists the named periods a range bound accepts.
+var RangePeriods = []string{
+	"today", "yesterday", "this-week", "last-week", "this-month", "last-month",
+	"billing-cycle", "last-billing-cycle",
+}
+
+// Parse builds the inclusive range for since and until. A named period
+// or a date starts the range at its beginning and ends it at its end, so
+// since=until=yesterday covers the whole of yesterday. Empty bounds are
+// open.
+func (c RangeContext) Parse(since, until string) (TimeRange, error) {
+	var r TimeRange
+	var err error
+	if since != "" {
+		if r.Since, err = c.bound(since, false); err != nil {
+			return TimeRange{}, err
+		}
+	}
+	if until != "" {
+		if r.Until, err = c.bound(until, true); err != nil {
+			return TimeRange{}, err
+		}
+	}
+	if !r.Since.IsZero() && !r.Until.IsZero() && r.Since.After(r.Until) {
+		return TimeRange{}, fmt.Errorf("range starts after it ends (%s)", r)
+	}
+	return r, nil
+}
+
+// bound resolves one bound; end selects the last instant of a period
+// rather than its first.
+func (c RangeContext) bound(s string, end bool) (time.Time, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if start, stop, ok := c.period(name); ok {
+		if end {
+			return stop.Add(-time.Nanosecond), nil
+		}
+		return start, nil
+	}
+	now := c.Now.In(c.Location)
+	if name == "now" {
+		return now, nil
+	}
+	if d, ok := parseLookback(name); ok {
+		return now.Add(-d), nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", name, c.Location); err == nil {
+		if end {
+			return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+		}
+		return day, nil
+	}
+	if t, err := ParseFilterTime(strings.TrimSpace(s), c.Location); err == nil {
+		return t.In(c.Location), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, RFC 3339, now, a duration such as 7d, 24h or 2w, or %s)",
+		s, strings.Join(RangePeriods, ", "))
+}
+
+// parseLookback parses a duration back from now: a count followed by h
+// (hours), d (days) or w (weeks).
+func parseLookback(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	switch s[len(s)-1] {
+	case 'h':
+		return time.Duration(n) * time.Hour, true
+	case 'd':
+		return time.Duration(n) * 24 * time.Hour, true
+	case 'w':
+		return time.Duration(n) * 7 * 24 * time.Hour, true
+	}
+	return 0, false
+}
+
+// period returns the start and exclusive end of a named period.
+func (c RangeContext) period(name string) (start, end time.Time, ok bool) {
+	now := c.Now.In(c.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.Location)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, c.Location)
+	switch name {
+	case "today":
+		return today, today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, true
+	case "this-week":
+		return monday, monday.AddDate(0, 0, 7), true
+	case "last-week":
+		return monday.AddDate(0, 0, -7), monday, true
+	case "this-month":
+		return month, month.AddDate(0, 1, 0), true
+	case "last-month":
+		return month.AddDate(0, -1, 0), month, true
+	case "billing-cycle":
+		start, end := BillingCycle(now, c.BillingDay)
+		return start, end, true
+	case "last-billing-cycle":
+		cur, _ := BillingCycle(now, c.BillingDay)
+		start, end := BillingCycle(cur.Add(-time.Nanosecond), c.BillingDay)
+		return start, end, true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// BillingCycle returns the start and exclusive end of the billing cycle
+// containing t, in t's location. A cycle starts at midnight on day of the
+// month, or on the last day of months that are shorter.
+func BillingCycle(t time.Time, day int) (start, end time.Time) {
+	if day < 1 {
+		day = 1
+	}
+	start = billingDate(t.Year(), t.Month(), day, t.Location())
+	if t.Before(start) {
+		start = billingDate(t.Year(), t.Month()-1, day, t.Location())
+	}
+	return start, billingDate(start.Year(), start.Month()+1, day, t.Location())
+}
+
+// billingDate returns midnight on day of the month, clamped to its length.
+func billingDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
+
+// String renders the range as RFC 3339 bounds, with … for an open bound.
+func (r TimeRange) String() string {
+	since, until := "…", "…"
+	if !r.Since.IsZero() {
+		since = r.Since.Format(time.RFC3339)
+	}
+	if !r.Until.IsZero() {
+		until = r.Until.Format(time.RFC3339)
+	}
+	return since + " – " + until
+}
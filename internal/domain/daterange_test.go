@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRangeContext_Parse(t *testing.T) {
+	tz := time.FixedZone("UTC+9", 9*3600)
+	// Wednesday 2026-03-11 10:30 in tz
+	rc := RangeContext{Now: time.Date(2026, 3, 11, 1, 30, 0, 0, time.UTC), Location: tz, BillingDay: 15}
+	at := func(y int, m time.Month, d, h, min int) time.Time { return time.Date(y, m, d, h, min, 0, 0, tz) }
+	endOf := func(y int, m time.Month, d int) time.Time { return at(y, m, d, 0, 0).AddDate(0, 0, 1).Add(-time.Nanosecond) }
+
+	tests := []struct {
+		since, until string
+		want         TimeRange
+	}{
+		{"today", "today", TimeRange{at(2026, 3, 11, 0, 0), endOf(2026, 3, 11)}},
+		{"yesterday", "yesterday", TimeRange{at(2026, 3, 10, 0, 0), endOf(2026, 3, 10)}},
+		{"7d", "", TimeRange{Since: at(2026, 3, 4, 10, 30)}},
+		{"24h", "now", TimeRange{at(2026, 3, 10, 10, 30), at(2026, 3, 11, 10, 30)}},
+		{"this-week", "this-week", TimeRange{at(2026, 3, 9, 0, 0), endOf(2026, 3, 15)}},
+		{"last-week", "", TimeRange{Since: at(2026, 3, 2, 0, 0)}},
+		{"last-month", "last-month", TimeRange{at(2026, 2, 1, 0, 0), endOf(2026, 2, 28)}},
+		{"this-month", "", TimeRange{Since: at(2026, 3, 1, 0, 0)}},
+		{"billing-cycle", "billing-cycle", TimeRange{at(2026, 2, 15, 0, 0), endOf(2026, 3, 14)}},
+		{"last-billing-cycle", "", TimeRange{Since: at(2026, 1, 15, 0, 0)}},
+		{"2026-03-01", "2026-03-02", TimeRange{at(2026, 3, 1, 0, 0), endOf(2026, 3, 2)}},
+		{"2026-03-10T13:00", "2026-03-10T18:00", TimeRange{at(2026, 3, 10, 13, 0), at(2026, 3, 10, 18, 0)}},
+		{"2026-03-10T04:00:00Z", "", TimeRange{Since: at(2026, 3, 10, 13, 0)}},
+		{"", "", TimeRange{}},
+	}
+	for _, tt := range tests {
+		got, err := rc.Parse(tt.since, tt.until)
+		if err != nil {
+			t.Errorf("Parse(%q, %q): %v", tt.since, tt.until, err)
+			continue
+		}
+		if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+			t.Errorf("Parse(%q, %q) = %s, want %s", tt.since, tt.until, got, tt.want)
+		}
+	}
+
+	for _, bad := range [][2]string{{"soon", ""}, {"", "7x"}, {"today", "yesterday"}} {
+		if _, err := rc.Parse(bad[0], bad[1]); err == nil {
+			t.Errorf("Parse(%q, %q) succeeded, want an error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestBillingCycle(t *testing.T) {
+	// A billing day past the end of a month falls on its last day
+	start, end := BillingCycle(time.Date(2026, 2, 20, 12, 0, 0, 0, time.UTC), 31)
+	if want := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("start = %v, want %v", start, want)
+	}
+	if want := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end = %v, want %v", end, want)
+	}
+	start, _ = BillingCycle(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 0)
+	if want := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("default day start = %v, want %v", start, want)
+	}
+}
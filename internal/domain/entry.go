@@ -13,7 +13,31 @@ type UsageEntry struct {
 	MessageID           string
 	RequestID           string
 	SessionID           string
-	ProjectPath         string // derived from file path
+	ProjectPath         string // derived from file path; resolved to the project root
+	ProjectName         string // display name of the resolved project
+	Source              string // Claude data directory the entry was read from
+
+	// Transcript metadata
+	Cwd         string // working directory of the Claude Code session
+	GitBranch   string
+	Version     string // Claude Code CLI version
+	IsSidechain bool   // subagent (Task) traffic
+	UserType    string // e.g. "external"
+	ServiceTier string // e.g. "standard", "priority"
+
+	// Usage details
+	WebSearchRequests     int // server_tool_use.web_search_requests
+	WebFetchRequests      int // server_tool_use.web_fetch_requests
+	CacheCreation5mTokens int // cache_creation.ephemeral_5m_input_tokens
+	CacheCreation1hTokens int // cache_creation.ephemeral_1h_input_tokens
+
+	// PromptID is the uuid of the user prompt whose parentUuid chain
+	// led to this message; empty if the chain could not be followed.
+	PromptID string
+
+	// Tools lists the names of the tool_use blocks of the message, in
+	// order; a tool called twice appears twice.
+	Tools []string
 }
 
 // TotalTokens returns input + output + cache tokens for limit comparison.
@@ -25,3 +49,59 @@ func (e UsageEntry) TotalTokens() int {
 func (e UsageEntry) DedupKey() string {
 	return e.MessageID + ":" + e.RequestID
 }
+
+// HasDedupKey reports whether the entry carries any identifier to
+// deduplicate by.
+func (e UsageEntry) HasDedupKey() bool {
+	return e.MessageID != "" || e.RequestID != ""
+}
+
+// KeyHash returns the 64-bit hash of DedupKey that a KeySet stores.
+func (e UsageEntry) KeyHash() uint64 {
+	return dedupHash(e)
+}
+
+// KeySet is a compact set of dedup keys. Keys are stored as 64-bit FNV-1a
+// hashes instead of strings, which keeps millions of keys in a few dozen
+// megabytes; a collision is vanishingly unlikely at that scale.
+type KeySet map[uint64]struct{}
+
+// Add records the entry's dedup key and reports whether it was not in the
+// set yet. Entries without a key are never recorded and always new.
+func (s KeySet) Add(e UsageEntry) bool {
+	if !e.HasDedupKey() {
+		return true
+	}
+	h := dedupHash(e)
+	if _, ok := s[h]; ok {
+		return false
+	}
+	s[h] = struct{}{}
+	return true
+}
+
+// Has reports whether the entry's dedup key is in the set.
+func (s KeySet) Has(e UsageEntry) bool {
+	if !e.HasDedupKey() {
+		return false
+	}
+	_, ok := s[dedupHash(e)]
+	return ok
+}
+
+// dedupHash is FNV-1a over DedupKey without building the string.
+func dedupHash(e UsageEntry) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(e.MessageID); i++ {
+		h = (h ^ uint64(e.MessageID[i])) * prime64
+	}
+	h = (h ^ ':') * prime64
+	for i := 0; i < len(e.RequestID); i++ {
+		h = (h ^ uint64(e.RequestID[i])) * prime64
+	}
+	return h
+}
@@ -40,3 +40,26 @@ func TestUsageEntry_DedupKey(t *testing.T) {
 		t.Errorf("DedupKey() = %q, want %q", got, want)
 	}
 }
+
+func TestKeySet(t *testing.T) {
+	s := make(KeySet)
+	a := UsageEntry{MessageID: "msg_1", RequestID: "req_1"}
+	swapped := UsageEntry{MessageID: "msg_1req_1"}
+
+	if !s.Add(a) {
+		t.Error("first Add should report a new key")
+	}
+	if s.Add(a) {
+		t.Error("second Add of the same key should report a duplicate")
+	}
+	if s.Has(swapped) || !s.Add(swapped) {
+		t.Error("keys differing only in the separator position must not collide")
+	}
+	keyless := UsageEntry{InputTokens: 1}
+	if !s.Add(keyless) || !s.Add(keyless) || s.Has(keyless) {
+		t.Error("entries without a key should never be recorded")
+	}
+	if len(s) != 2 {
+		t.Errorf("len = %d, want 2", len(s))
+	}
+}
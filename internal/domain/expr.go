@@ -0,0 +1,402 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// FilterExpr is a compiled filter expression such as
+//
+//	model~opus and project=foo and cost>0.5 and branch!=main
+//
+// Comparisons are joined with and, or and not, and grouped with
+// parentheses. Text fields compare case-insensitively: = and != test
+// equality, ~ and !~ test for a substring. Numbers and times support
+// =, !=, <, <=, > and >=. Values containing spaces or operators are
+// quoted with "" or ''. A nil *FilterExpr matches every entry.
+type FilterExpr struct {
+	src   string
+	match func(UsageEntry) bool
+}
+
+// FilterFields lists the fields a filter expression can compare, in
+// documentation order.
+var FilterFields = []string{
+	"model", "project", "session", "branch", "version", "source", "cwd",
+	"cost", "tokens", "input", "output", "cache_create", "cache_read",
+	"time", "date", "hour", "weekday", "sidechain",
+}
+
+// ParseFilterExpr compiles a filter expression. Dates, times, hours and
+// weekdays are interpreted in tz. An empty expression returns nil.
+func ParseFilterExpr(src string, tz *time.Location) (*FilterExpr, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	toks, err := tokenizeFilter(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{toks: toks, tz: tz}
+	m, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos+1)
+	}
+	return &FilterExpr{src: strings.TrimSpace(src), match: m}, nil
+}
+
+// String returns the source of the expression.
+func (f *FilterExpr) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.src
+}
+
+// Match reports whether the entry satisfies the expression.
+func (f *FilterExpr) Match(e UsageEntry) bool {
+	return f == nil || f.match(e)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString // quoted value
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// filterOps lists the comparison operators, longest first.
+var filterOps = []string{"!=", "!~", ">=", "<=", "=", "~", ">", "<"}
+
+func isFilterWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`()=!~<>"'`, r)
+}
+
+func tokenizeFilter(src string) ([]filterToken, error) {
+	var toks []filterToken
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, filterToken{tokLParen, "(", i})
+			i++
+		case r == ')':
+			toks = append(toks, filterToken{tokRParen, ")", i})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(rs) && rs[end] != r {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("unterminated quote at position %d", i+1)
+			}
+			toks = append(toks, filterToken{tokString, string(rs[i+1 : end]), i})
+			i = end + 1
+		case strings.ContainsRune("=!~<>", r):
+			op := ""
+			for _, o := range filterOps {
+				if strings.HasPrefix(string(rs[i:]), o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unknown operator at position %d", i+1)
+			}
+			toks = append(toks, filterToken{tokOp, op, i})
+			i += len(op)
+		default:
+			end := i
+			for end < len(rs) && isFilterWordRune(rs[end]) {
+				end++
+			}
+			toks = append(toks, filterToken{tokWord, string(rs[i:end]), i})
+			i = end
+		}
+	}
+	return append(toks, filterToken{kind: tokEOF, pos: len(rs)}), nil
+}
+
+type filterParser struct {
+	toks []filterToken
+	i    int
+	tz   *time.Location
+}
+
+func (p *filterParser) peek() filterToken { return p.toks[p.i] }
+
+func (p *filterParser) next() filterToken {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the keyword kw and consumes it.
+func (p *filterParser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && strings.EqualFold(t.text, kw) {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (func(UsageEntry) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e UsageEntry) bool { return l(e) || right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (func(UsageEntry) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e UsageEntry) bool { return l(e) && right(e) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (func(UsageEntry) bool, error) {
+	if p.keyword("not") {
+		m, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(e UsageEntry) bool { return !m(e) }, nil
+	}
+	if p.peek().kind == tokLParen {
+		open := p.next()
+		m, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, fmt.Errorf("missing ) for ( at position %d", open.pos+1)
+		}
+		return m, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (func(UsageEntry) bool, error) {
+	field := p.next()
+	if field.kind != tokWord {
+		if field.kind == tokEOF {
+			return nil, fmt.Errorf("expected a comparison at the end")
+		}
+		return nil, fmt.Errorf("expected a field at position %d, got %q", field.pos+1, field.text)
+	}
+	op := p.next()
+	if op.kind != tokOp {
+		return nil, fmt.Errorf("expected an operator after %q", field.text)
+	}
+	val := p.next()
+	if val.kind != tokWord && val.kind != tokString {
+		return nil, fmt.Errorf("expected a value after %s%s", field.text, op.text)
+	}
+	m, err := p.compare(strings.ToLower(field.text), op.text, val.text)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s%s: %w", field.text, op.text, val.text, err)
+	}
+	return m, nil
+}
+
+// compare compiles one comparison.
+func (p *filterParser) compare(field, op, val string) (func(UsageEntry) bool, error) {
+	tz := p.tz
+	switch field {
+	case "model":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.Model} })
+	case "project":
+		// Equality tests the display name; ~ also searches the root path
+		return compareText(op, val, func(e UsageEntry) []string {
+			if op == "~" || op == "!~" {
+				return []string{e.ProjectName, e.ProjectPath}
+			}
+			return []string{e.ProjectName}
+		})
+	case "session":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.SessionID} })
+	case "branch":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.GitBranch} })
+	case "version":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.Version} })
+	case "source":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.Source} })
+	case "cwd":
+		return compareText(op, val, func(e UsageEntry) []string { return []string{e.Cwd} })
+	case "weekday":
+		return compareText(op, val, func(e UsageEntry) []string {
+			day := e.Timestamp.In(tz).Weekday().String()
+			return []string{day, day[:3]}
+		})
+	case "cost":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return e.CostUSD })
+	case "tokens":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.TotalTokens()) })
+	case "input":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.InputTokens) })
+	case "output":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.OutputTokens) })
+	case "cache_create":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.CacheCreationTokens) })
+	case "cache_read":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.CacheReadTokens) })
+	case "hour":
+		return compareNumber(op, val, func(e UsageEntry) float64 { return float64(e.Timestamp.In(tz).Hour()) })
+	case "time":
+		t, err := ParseFilterTime(val, tz)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(op, func(e UsageEntry) int { return e.Timestamp.Compare(t) })
+	case "date":
+		d, err := time.ParseInLocation("2006-01-02", val, tz)
+		if err != nil {
+			return nil, fmt.Errorf("date must be YYYY-MM-DD")
+		}
+		day := d.Format("2006-01-02")
+		return compareOrdered(op, func(e UsageEntry) int {
+			return strings.Compare(e.Timestamp.In(tz).Format("2006-01-02"), day)
+		})
+	case "sidechain":
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("sidechain must be true or false")
+		}
+		switch op {
+		case "=":
+			return func(e UsageEntry) bool { return e.IsSidechain == b }, nil
+		case "!=":
+			return func(e UsageEntry) bool { return e.IsSidechain != b }, nil
+		}
+		return nil, fmt.Errorf("sidechain supports = and !=")
+	}
+	return nil, fmt.Errorf("unknown field (use %s)", strings.Join(FilterFields, ", "))
+}
+
+// compareText matches when any of the values satisfies op.
+func compareText(op, val string, values func(UsageEntry) []string) (func(UsageEntry) bool, error) {
+	want := strings.ToLower(val)
+	var test func(string) bool
+	negate := false
+	switch op {
+	case "=":
+		test = func(s string) bool { return strings.ToLower(s) == want }
+	case "!=":
+		test, negate = func(s string) bool { return strings.ToLower(s) == want }, true
+	case "~":
+		test = func(s string) bool { return strings.Contains(strings.ToLower(s), want) }
+	case "!~":
+		test, negate = func(s string) bool { return strings.Contains(strings.ToLower(s), want) }, true
+	default:
+		return nil, fmt.Errorf("text fields support =, !=, ~ and !~")
+	}
+	return func(e UsageEntry) bool {
+		for _, s := range values(e) {
+			if test(s) {
+				return !negate
+			}
+		}
+		return negate
+	}, nil
+}
+
+func compareNumber(op, val string, value func(UsageEntry) float64) (func(UsageEntry) bool, error) {
+	want, err := strconv.ParseFloat(strings.TrimPrefix(val, "$"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a number", val)
+	}
+	return compareOrdered(op, func(e UsageEntry) int {
+		v := value(e)
+		switch {
+		case v < want:
+			return -1
+		case v > want:
+			return 1
+		}
+		return 0
+	})
+}
+
+// compareOrdered builds a comparison from cmp, which returns the sign of
+// the entry's value minus the wanted one.
+func compareOrdered(op string, cmp func(UsageEntry) int) (func(UsageEntry) bool, error) {
+	switch op {
+	case "=":
+		return func(e UsageEntry) bool { return cmp(e) == 0 }, nil
+	case "!=":
+		return func(e UsageEntry) bool { return cmp(e) != 0 }, nil
+	case "<":
+		return func(e UsageEntry) bool { return cmp(e) < 0 }, nil
+	case "<=":
+		return func(e UsageEntry) bool { return cmp(e) <= 0 }, nil
+	case ">":
+		return func(e UsageEntry) bool { return cmp(e) > 0 }, nil
+	case ">=":
+		return func(e UsageEntry) bool { return cmp(e) >= 0 }, nil
+	}
+	return nil, fmt.Errorf("numbers and times do not support %s", op)
+}
+
+// filterTimeLayouts are the timestamp layouts ParseFilterTime accepts
+// besides RFC 3339; they are read in the filter's timezone.
+var filterTimeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseFilterTime parses an RFC 3339 timestamp, or a date with an
+// optional time of day in tz.
+func ParseFilterTime(s string, tz *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, tz); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a time (use YYYY-MM-DD, YYYY-MM-DDTHH:MM or RFC 3339)", s)
+}
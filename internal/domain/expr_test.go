@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFilterExpr(t *testing.T) {
+	tz := time.FixedZone("UTC+9", 9*3600)
+	// Tuesday 2026-03-10 15:30 in tz
+	e := UsageEntry{
+		Timestamp:    time.Date(2026, 3, 10, 6, 30, 0, 0, time.UTC),
+		Model:        "claude-opus-4-6",
+		ProjectName:  "foo",
+		ProjectPath:  "/home/me/src/foo",
+		GitBranch:    "feature/x",
+		OutputTokens: 1000,
+		CostUSD:      0.75,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"model~opus and project=foo and cost>0.5 and branch!=main", true},
+		{"model~OPUS", true},
+		{"model=opus", false},
+		{"model!~sonnet", true},
+		{"project~src/foo", true},
+		{"project=src", false},
+		{"cost>=0.75 and cost<=0.75", true},
+		{"cost>$1", false},
+		{"output=1000", true},
+		{"tokens<1000", false},
+		{"hour>=12 and hour<18", true},
+		{"weekday=tue", true},
+		{"weekday=Tuesday and date=2026-03-10", true},
+		{"date<2026-03-10", false},
+		{"time>=2026-03-10T15:00 and time<2026-03-10T16:00", true},
+		{`time>"2026-03-10 15:31"`, false},
+		{"time<2026-03-10T07:00:00Z", true},
+		{"sidechain=false", true},
+		{"model~sonnet or branch~feature", true},
+		{"not (model~sonnet or cost<0.1)", true},
+		{"model~sonnet or branch~feature and cost>5", false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFilterExpr(tt.expr, tz)
+		if err != nil {
+			t.Errorf("ParseFilterExpr(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := f.Match(e); got != tt.want {
+			t.Errorf("%q matched = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilterExpr_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"modle=opus",
+		"model",
+		"model=",
+		"cost~1",
+		"cost>cheap",
+		"time>yesterday",
+		"(model=opus",
+		"model=opus and",
+		"model=opus)",
+		`model="opus`,
+		"sidechain>true",
+	} {
+		if _, err := ParseFilterExpr(expr, time.UTC); err == nil {
+			t.Errorf("ParseFilterExpr(%q) succeeded, want an error", expr)
+		}
+	}
+
+	f, err := ParseFilterExpr("  ", time.UTC)
+	if err != nil || f != nil {
+		t.Errorf("empty expression = %v, %v; want nil, nil", f, err)
+	}
+	if !f.Match(UsageEntry{}) {
+		t.Error("nil filter should match every entry")
+	}
+}
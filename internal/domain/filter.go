@@ -2,40 +2,102 @@ package domain
 
 import "time"
 
-// FilterByTimeRange returns entries within the [since, until] date range.
-// Both boundaries are inclusive (until includes the entire end-of-day).
-// Empty date strings mean no constraint on that boundary.
+// FilterByTimeRange returns entries within the [since, until] range.
+// Bounds are parsed by RangeContext.Parse relative to now, with billing
+// cycles starting on the 1st; a date includes the entire end-of-day.
+// Empty strings mean no constraint on that boundary.
 func FilterByTimeRange(entries []UsageEntry, since, until string, tz *time.Location) ([]UsageEntry, error) {
 	if since == "" && until == "" {
 		return entries, nil
 	}
 
-	var sinceTime, untilTime time.Time
-	if since != "" {
-		t, err := time.ParseInLocation("2006-01-02", since, tz)
-		if err != nil {
-			return nil, err
-		}
-		sinceTime = t
+	r, err := RangeContext{Now: time.Now(), Location: tz}.Parse(since, until)
+	if err != nil {
+		return nil, err
+	}
+	return FilterByRange(entries, r), nil
+}
+
+// FilterByRange returns the entries within r.
+func FilterByRange(entries []UsageEntry, r TimeRange) []UsageEntry {
+	if r.Since.IsZero() && r.Until.IsZero() {
+		return entries
 	}
-	if until != "" {
-		t, err := time.ParseInLocation("2006-01-02", until, tz)
-		if err != nil {
-			return nil, err
+	filtered := make([]UsageEntry, 0, len(entries))
+	for _, e := range entries {
+		if r.Contains(e.Timestamp) {
+			filtered = append(filtered, e)
 		}
-		untilTime = t.Add(24*time.Hour - time.Nanosecond) // end of day
 	}
+	return filtered
+}
+
+// TimeRange is an inclusive time interval. A zero bound is open.
+type TimeRange struct {
+	Since time.Time
+	Until time.Time
+}
+
+// Contains reports whether t falls within the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Since.IsZero() && t.Before(r.Since) {
+		return false
+	}
+	if !r.Until.IsZero() && t.After(r.Until) {
+		return false
+	}
+	return true
+}
+
+// SidechainMode selects how subagent (sidechain) traffic is filtered.
+type SidechainMode string
+
+const (
+	SidechainInclude SidechainMode = ""        // keep all entries
+	SidechainOnly    SidechainMode = "only"    // keep only subagent entries
+	SidechainExclude SidechainMode = "exclude" // drop subagent entries
+)
+
+// MetadataFilter narrows entries by transcript metadata.
+// Empty fields match everything.
+type MetadataFilter struct {
+	Branch    string
+	Version   string
+	Sidechain SidechainMode
+}
+
+// IsZero reports whether the filter matches every entry.
+func (f MetadataFilter) IsZero() bool {
+	return f == MetadataFilter{}
+}
+
+// Match reports whether a single entry passes the filter.
+func (f MetadataFilter) Match(e UsageEntry) bool {
+	if f.Branch != "" && e.GitBranch != f.Branch {
+		return false
+	}
+	if f.Version != "" && e.Version != f.Version {
+		return false
+	}
+	switch f.Sidechain {
+	case SidechainOnly:
+		return e.IsSidechain
+	case SidechainExclude:
+		return !e.IsSidechain
+	}
+	return true
+}
 
+// FilterByMetadata returns the entries that pass the metadata filter.
+func FilterByMetadata(entries []UsageEntry, f MetadataFilter) []UsageEntry {
+	if f.IsZero() {
+		return entries
+	}
 	filtered := make([]UsageEntry, 0, len(entries))
 	for _, e := range entries {
-		local := e.Timestamp.In(tz)
-		if !sinceTime.IsZero() && local.Before(sinceTime) {
-			continue
-		}
-		if !untilTime.IsZero() && local.After(untilTime) {
-			continue
+		if f.Match(e) {
+			filtered = append(filtered, e)
 		}
-		filtered = append(filtered, e)
 	}
-	return filtered, nil
+	return filtered
 }
@@ -62,3 +62,31 @@ func TestFilterByTimeRange_EmptyEntries(t *testing.T) {
 		t.Errorf("got %d entries, want 0", len(result))
 	}
 }
+
+func TestFilterByMetadata(t *testing.T) {
+	entries := []UsageEntry{
+		{GitBranch: "main", Version: "2.0.1"},
+		{GitBranch: "feature", Version: "2.0.1", IsSidechain: true},
+		{GitBranch: "main", Version: "2.0.2", IsSidechain: true},
+	}
+
+	tests := []struct {
+		name   string
+		filter MetadataFilter
+		want   int
+	}{
+		{"zero filter keeps all", MetadataFilter{}, 3},
+		{"branch", MetadataFilter{Branch: "main"}, 2},
+		{"version", MetadataFilter{Version: "2.0.1"}, 2},
+		{"sidechain only", MetadataFilter{Sidechain: SidechainOnly}, 2},
+		{"sidechain exclude", MetadataFilter{Sidechain: SidechainExclude}, 1},
+		{"combined", MetadataFilter{Branch: "main", Sidechain: SidechainOnly}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(FilterByMetadata(entries, tt.filter)); got != tt.want {
+				t.Errorf("got %d entries, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// Forecasts extrapolate an hourly usage rate in which recent hours weigh
+// more: an hour ForecastHalfLife old counts half as much as the current
+// one. Hours before the first entry are left out, so a short history is
+// not diluted by the idle time before it.
+const (
+	ForecastHalfLife = 72 * time.Hour
+	forecastLookback = 14 * 24 * time.Hour
+	forecastZ        = 1.645 // two-sided 90% band
+)
+
+// ForecastOptions sets the time and the periods a forecast projects.
+type ForecastOptions struct {
+	Now      time.Time
+	Location *time.Location // days and months
+	Weekly   *WeeklyOptions // places the 7-day window; nil skips it
+}
+
+// Estimate projects one quantity to the end of a period.
+type Estimate struct {
+	Actual    float64 // so far in the period
+	Projected float64 // expected by the end of the period
+	Low       float64 // 90% band, never below Actual
+	High      float64
+	PerHour   float64 // weighted recent rate
+}
+
+// Projection forecasts cost and tokens at the end of a period.
+type Projection struct {
+	Start  time.Time
+	End    time.Time
+	Cost   Estimate
+	Tokens Estimate
+}
+
+// Remaining returns the time left in the period at now.
+func (p Projection) Remaining(now time.Time) time.Duration {
+	return max(p.End.Sub(now), 0)
+}
+
+// Forecast projects today, the calendar month and the 7-day window.
+type Forecast struct {
+	Now   time.Time
+	Today Projection
+	Month Projection
+	Week  *Projection `json:",omitempty"` // nil without weekly options
+}
+
+// BuildForecast forecasts from entries.
+func BuildForecast(entries []UsageEntry, opts ForecastOptions) Forecast {
+	acc := NewForecastAccumulator(opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Forecast()
+}
+
+// ForecastAccumulator builds a forecast one entry at a time. It holds the
+// totals of each hour within the lookback.
+type ForecastAccumulator struct {
+	opts     ForecastOptions
+	periods  []*Projection // today, month and optionally week
+	forecast Forecast
+	hours    map[int64]*hourUsage // by Unix hour
+	first    time.Time            // earliest entry within the lookback
+}
+
+type hourUsage struct {
+	cost   float64
+	tokens float64
+}
+
+// NewForecastAccumulator returns an empty accumulator.
+func NewForecastAccumulator(opts ForecastOptions) *ForecastAccumulator {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	now := opts.Now.In(opts.Location)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, opts.Location)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, opts.Location)
+
+	a := &ForecastAccumulator{opts: opts, hours: make(map[int64]*hourUsage)}
+	a.forecast.Now = opts.Now
+	a.forecast.Today = Projection{Start: day, End: day.AddDate(0, 0, 1)}
+	a.forecast.Month = Projection{Start: month, End: month.AddDate(0, 1, 0)}
+	a.periods = []*Projection{&a.forecast.Today, &a.forecast.Month}
+	if opts.Weekly != nil {
+		start, end := opts.Weekly.Window(opts.Now)
+		a.forecast.Week = &Projection{Start: start, End: end}
+		a.periods = append(a.periods, a.forecast.Week)
+	}
+	return a
+}
+
+// Add accumulates one entry.
+func (a *ForecastAccumulator) Add(e UsageEntry) {
+	t := e.Timestamp
+	cost, tokens := e.CostUSD, float64(e.TotalTokens())
+	for _, p := range a.periods {
+		if !t.Before(p.Start) && t.Before(p.End) {
+			p.Cost.Actual += cost
+			p.Tokens.Actual += tokens
+		}
+	}
+	if t.Before(a.opts.Now.Add(-forecastLookback)) || t.After(a.opts.Now) {
+		return
+	}
+	if a.first.IsZero() || t.Before(a.first) {
+		a.first = t
+	}
+	key := t.Unix() / 3600
+	h := a.hours[key]
+	if h == nil {
+		h = &hourUsage{}
+		a.hours[key] = h
+	}
+	h.cost += cost
+	h.tokens += tokens
+}
+
+// Forecast returns the projections at the accumulator's time.
+func (a *ForecastAccumulator) Forecast() Forecast {
+	costRate, costSD := a.rate(func(h hourUsage) float64 { return h.cost })
+	tokenRate, tokenSD := a.rate(func(h hourUsage) float64 { return h.tokens })
+	for _, p := range a.periods {
+		hours := p.Remaining(a.opts.Now).Hours()
+		p.Cost = project(p.Cost.Actual, costRate, costSD, hours)
+		p.Tokens = project(p.Tokens.Actual, tokenRate, tokenSD, hours)
+	}
+	return a.forecast
+}
+
+// project extrapolates actual over hours at rate, with a band that widens
+// with the square root of the hours left.
+func project(actual, rate, sd, hours float64) Estimate {
+	projected := actual + rate*hours
+	band := forecastZ * sd * math.Sqrt(hours)
+	return Estimate{
+		Actual:    actual,
+		Projected: projected,
+		Low:       math.Max(actual, projected-band),
+		High:      projected + band,
+		PerHour:   rate,
+	}
+}
+
+// rate returns the weighted mean and standard deviation of the hourly
+// usage over the complete hours since the first entry. Without a complete
+// hour, the usage so far is extrapolated with no spread.
+func (a *ForecastAccumulator) rate(value func(hourUsage) float64) (mean, sd float64) {
+	if a.first.IsZero() {
+		return 0, 0
+	}
+	nowHour := a.opts.Now.Unix() / 3600
+	firstHour := a.first.Unix() / 3600
+	if firstHour >= nowHour {
+		var v float64
+		if h := a.hours[nowHour]; h != nil {
+			v = value(*h)
+		}
+		elapsed := math.Max(a.opts.Now.Sub(a.first).Hours(), 1.0/60)
+		return v / elapsed, 0
+	}
+
+	var sumW, sumWX float64
+	xs := make([]float64, 0, nowHour-firstHour)
+	ws := make([]float64, 0, nowHour-firstHour)
+	for k := firstHour; k < nowHour; k++ {
+		var x float64
+		if h := a.hours[k]; h != nil {
+			x = value(*h)
+		}
+		age := a.opts.Now.Sub(time.Unix((k+1)*3600, 0)).Hours()
+		w := math.Exp2(-age / ForecastHalfLife.Hours())
+		xs, ws = append(xs, x), append(ws, w)
+		sumW += w
+		sumWX += w * x
+	}
+	mean = sumWX / sumW
+	var sumWD float64
+	for i, x := range xs {
+		sumWD += ws[i] * (x - mean) * (x - mean)
+	}
+	return mean, math.Sqrt(sumWD / sumW)
+}
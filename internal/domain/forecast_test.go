@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildForecast(t *testing.T) {
+	// 2026-03-11 12:00 UTC; March has 31 days, so 20.5 days are left
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	var entries []UsageEntry
+	// $1 and 100 tokens every hour for the last two days
+	for h := 1; h <= 48; h++ {
+		entries = append(entries, UsageEntry{Timestamp: now.Add(-time.Duration(h)*time.Hour + time.Minute), InputTokens: 100, CostUSD: 1})
+	}
+	// Last month counts toward nothing but the rate's lookback bound
+	entries = append(entries, UsageEntry{Timestamp: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), CostUSD: 50})
+
+	f := BuildForecast(entries, ForecastOptions{
+		Now:      now,
+		Location: time.UTC,
+		Weekly:   &WeeklyOptions{ResetDay: time.Monday, Location: time.UTC},
+	})
+
+	approx := func(name string, got, want float64) {
+		t.Helper()
+		if math.Abs(got-want) > 1e-6 {
+			t.Errorf("%s = %f, want %f", name, got, want)
+		}
+	}
+	approx("today actual", f.Today.Cost.Actual, 12)
+	approx("rate", f.Today.Cost.PerHour, 1)
+	approx("today projected", f.Today.Cost.Projected, 24)
+	approx("month actual", f.Month.Cost.Actual, 48)
+	approx("month projected", f.Month.Cost.Projected, 48+20.5*24)
+	approx("month tokens projected", f.Month.Tokens.Projected, 4800+20.5*24*100)
+	// A constant rate has no spread
+	approx("band", f.Month.Cost.High-f.Month.Cost.Low, 0)
+
+	if f.Week == nil {
+		t.Fatal("Week = nil, want a projection")
+	}
+	if want := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC); !f.Week.Start.Equal(want) {
+		t.Errorf("week start = %v, want %v", f.Week.Start, want)
+	}
+	approx("week projected", f.Week.Cost.Projected, 48+(4*24+12))
+}
+
+func TestBuildForecast_Band(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	var entries []UsageEntry
+	// Busy every other hour
+	for h := 1; h <= 24; h++ {
+		if h%2 == 0 {
+			entries = append(entries, UsageEntry{Timestamp: now.Add(-time.Duration(h) * time.Hour), CostUSD: 2})
+		}
+	}
+	f := BuildForecast(entries, ForecastOptions{Now: now, Location: time.UTC})
+	est := f.Today.Cost
+	if !(est.Low < est.Projected && est.Projected < est.High) {
+		t.Errorf("band = %f < %f < %f, want a spread around the projection", est.Low, est.Projected, est.High)
+	}
+	if est.Low < est.Actual {
+		t.Errorf("low %f below actual %f", est.Low, est.Actual)
+	}
+	if f.Week != nil {
+		t.Error("Week set without weekly options")
+	}
+
+	// Without history there is nothing to project
+	empty := BuildForecast(nil, ForecastOptions{Now: now})
+	if empty.Month.Cost.Projected != 0 {
+		t.Errorf("empty projection = %f, want 0", empty.Month.Cost.Projected)
+	}
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// HeatmapCell holds the usage of one weekday and hour.
+type HeatmapCell struct {
+	Tokens   int
+	Cost     float64
+	Messages int
+}
+
+// HeatmapSlot is a cell together with its position.
+type HeatmapSlot struct {
+	Day     time.Weekday `json:"-"` // index into Heatmap.Cells
+	Weekday string       // "Monday"
+	Hour    int          // 0-23
+	HeatmapCell
+}
+
+// Heatmap holds usage by weekday and hour of day.
+type Heatmap struct {
+	Timezone string
+	Cells    [7][24]HeatmapCell // [time.Weekday][hour], Sunday first
+	MaxCost  float64            // cost of the most expensive cell
+}
+
+// Peaks returns the n most expensive cells with usage, most expensive
+// first (ties broken by weekday, then hour).
+func (h Heatmap) Peaks(n int) []HeatmapSlot {
+	var slots []HeatmapSlot
+	for d := range h.Cells {
+		for hour, c := range h.Cells[d] {
+			if c.Messages > 0 {
+				slots = append(slots, HeatmapSlot{Day: time.Weekday(d), Weekday: time.Weekday(d).String(), Hour: hour, HeatmapCell: c})
+			}
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].Cost > slots[j].Cost })
+	if len(slots) > n {
+		slots = slots[:n]
+	}
+	return slots
+}
+
+// AggregateHeatmap buckets entries by weekday and hour in tz.
+func AggregateHeatmap(entries []UsageEntry, tz *time.Location) Heatmap {
+	acc := NewHeatmapAccumulator(tz)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Heatmap()
+}
+
+// HeatmapAccumulator builds a heatmap one entry at a time.
+type HeatmapAccumulator struct {
+	tz      *time.Location
+	heatmap Heatmap
+}
+
+// NewHeatmapAccumulator returns an empty accumulator bucketing in tz.
+func NewHeatmapAccumulator(tz *time.Location) *HeatmapAccumulator {
+	return &HeatmapAccumulator{tz: tz, heatmap: Heatmap{Timezone: tz.String()}}
+}
+
+// Add accumulates one entry.
+func (a *HeatmapAccumulator) Add(e UsageEntry) {
+	t := e.Timestamp.In(a.tz)
+	c := &a.heatmap.Cells[t.Weekday()][t.Hour()]
+	c.Tokens += e.TotalTokens()
+	c.Cost += e.CostUSD
+	c.Messages++
+	if c.Cost > a.heatmap.MaxCost {
+		a.heatmap.MaxCost = c.Cost
+	}
+}
+
+// Heatmap returns the accumulated heatmap.
+func (a *HeatmapAccumulator) Heatmap() Heatmap {
+	return a.heatmap
+}
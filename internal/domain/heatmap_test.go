@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateHeatmap(t *testing.T) {
+	tz := time.FixedZone("UTC+9", 9*3600)
+	entries := []UsageEntry{
+		// Sunday 23:30 UTC is Monday 08:30 in tz
+		{Timestamp: time.Date(2026, 3, 1, 23, 30, 0, 0, time.UTC), InputTokens: 100, CostUSD: 1.0},
+		{Timestamp: time.Date(2026, 3, 8, 23, 10, 0, 0, time.UTC), InputTokens: 50, CostUSD: 0.5},
+		// Wednesday 14:00 in tz
+		{Timestamp: time.Date(2026, 3, 4, 5, 0, 0, 0, time.UTC), OutputTokens: 20, CostUSD: 3.0},
+	}
+
+	hm := AggregateHeatmap(entries, tz)
+	mon := hm.Cells[time.Monday][8]
+	if mon.Messages != 2 || mon.Tokens != 150 || mon.Cost != 1.5 {
+		t.Errorf("Monday 08:00 = %+v, want 2 messages, 150 tokens, $1.5", mon)
+	}
+	if got := hm.Cells[time.Sunday][23].Messages; got != 0 {
+		t.Errorf("Sunday 23:00 messages = %d, want 0 (entries belong to tz)", got)
+	}
+	if hm.MaxCost != 3.0 {
+		t.Errorf("MaxCost = %f, want 3.0", hm.MaxCost)
+	}
+	if hm.Timezone != "UTC+9" {
+		t.Errorf("Timezone = %q, want UTC+9", hm.Timezone)
+	}
+
+	peaks := hm.Peaks(5)
+	if len(peaks) != 2 {
+		t.Fatalf("Peaks = %d slots, want 2", len(peaks))
+	}
+	if peaks[0].Day != time.Wednesday || peaks[0].Weekday != "Wednesday" || peaks[0].Hour != 14 {
+		t.Errorf("top peak = %s %d, want Wednesday 14", peaks[0].Weekday, peaks[0].Hour)
+	}
+	if got := hm.Peaks(1); len(got) != 1 {
+		t.Errorf("Peaks(1) = %d slots, want 1", len(got))
+	}
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ProjectAggregate holds usage totals for one project.
+type ProjectAggregate struct {
+	ProjectPath string // project root; "" for entries without one
+	ProjectName string
+	Totals
+	Share        float64 // percent of the cost of all projects
+	MessageCount int
+	ActiveDays   int // days with usage, in the aggregation timezone
+	Sessions     int
+	LastActivity time.Time
+}
+
+// AggregateByProject groups entries by project root, most expensive first.
+// Active days are counted in tz.
+func AggregateByProject(entries []UsageEntry, tz *time.Location) []ProjectAggregate {
+	acc := NewProjectAccumulator(tz)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Projects()
+}
+
+// ProjectAccumulator builds project aggregates one entry at a time. It
+// holds the distinct days and sessions of each project.
+type ProjectAccumulator struct {
+	tz       *time.Location
+	projects map[string]*projectTotals
+}
+
+type projectTotals struct {
+	agg      ProjectAggregate
+	days     map[string]struct{}
+	sessions map[string]struct{}
+}
+
+// NewProjectAccumulator returns an empty accumulator counting days in tz.
+func NewProjectAccumulator(tz *time.Location) *ProjectAccumulator {
+	return &ProjectAccumulator{tz: tz, projects: make(map[string]*projectTotals)}
+}
+
+// Add accumulates one entry.
+func (a *ProjectAccumulator) Add(e UsageEntry) {
+	p, ok := a.projects[e.ProjectPath]
+	if !ok {
+		p = &projectTotals{
+			agg:      ProjectAggregate{ProjectPath: e.ProjectPath, ProjectName: e.ProjectName},
+			days:     make(map[string]struct{}),
+			sessions: make(map[string]struct{}),
+		}
+		a.projects[e.ProjectPath] = p
+	}
+	p.agg.Add(e)
+	p.agg.MessageCount++
+	if e.Timestamp.After(p.agg.LastActivity) {
+		p.agg.LastActivity = e.Timestamp
+	}
+	p.days[e.Timestamp.In(a.tz).Format("2006-01-02")] = struct{}{}
+	if e.SessionID != "" {
+		p.sessions[e.SessionID] = struct{}{}
+	}
+}
+
+// Projects returns the aggregates sorted by cost descending (ties broken
+// by name, then root).
+func (a *ProjectAccumulator) Projects() []ProjectAggregate {
+	var total float64
+	for _, p := range a.projects {
+		total += p.agg.TotalCost
+	}
+	result := make([]ProjectAggregate, 0, len(a.projects))
+	for _, p := range a.projects {
+		agg := p.agg
+		agg.ActiveDays = len(p.days)
+		agg.Sessions = len(p.sessions)
+		if total > 0 {
+			agg.Share = agg.TotalCost / total * 100
+		}
+		result = append(result, agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		if result[i].ProjectName != result[j].ProjectName {
+			return result[i].ProjectName < result[j].ProjectName
+		}
+		return result[i].ProjectPath < result[j].ProjectPath
+	})
+	return result
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateByProject(t *testing.T) {
+	seoul := time.FixedZone("KST", 9*3600)
+	base := time.Date(2026, 2, 21, 14, 0, 0, 0, time.UTC) // 23:00 KST
+	entries := []UsageEntry{
+		{ProjectPath: "/src/app", ProjectName: "app", SessionID: "s1", Timestamp: base, InputTokens: 100, CacheReadTokens: 50, CostUSD: 3},
+		{ProjectPath: "/src/app", ProjectName: "app", SessionID: "s1", Timestamp: base.Add(2 * time.Hour), OutputTokens: 20, CostUSD: 3}, // next day in KST
+		{ProjectPath: "/src/app", ProjectName: "app", SessionID: "s2", Timestamp: base.Add(3 * time.Hour), CostUSD: 0},
+		{ProjectPath: "/src/lib", ProjectName: "lib", SessionID: "s3", Timestamp: base.Add(time.Hour), InputTokens: 10, CostUSD: 2},
+	}
+
+	projects := AggregateByProject(entries, seoul)
+	if len(projects) != 2 {
+		t.Fatalf("got %d projects, want 2", len(projects))
+	}
+	app, lib := projects[0], projects[1]
+	if app.ProjectPath != "/src/app" || lib.ProjectPath != "/src/lib" {
+		t.Fatalf("order = %s, %s; want /src/app, /src/lib", app.ProjectPath, lib.ProjectPath)
+	}
+	if app.TotalCost != 6 || app.Share != 75 || lib.Share != 25 {
+		t.Errorf("app = $%.0f (%.0f%%), lib %.0f%%; want $6 (75%%), 25%%", app.TotalCost, app.Share, lib.Share)
+	}
+	if app.ActiveDays != 2 || app.Sessions != 2 || app.MessageCount != 3 {
+		t.Errorf("app = %d days, %d sessions, %d msgs; want 2, 2, 3", app.ActiveDays, app.Sessions, app.MessageCount)
+	}
+	if app.TotalTokens() != 170 || !app.LastActivity.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("app = %d tokens, last %v; want 170, %v", app.TotalTokens(), app.LastActivity, base.Add(3*time.Hour))
+	}
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Prompt is a user prompt that started a chain of assistant messages.
+type Prompt struct {
+	ID        string // uuid of the user record
+	SessionID string
+	Timestamp time.Time
+	Text      string // preview, truncated at parse time
+}
+
+// PromptCost totals the assistant messages a prompt started, including
+// tool-use round trips and subagent traffic.
+type PromptCost struct {
+	PromptID    string
+	SessionID   string
+	ProjectName string
+	Preview     string
+	Start       time.Time // when the prompt was sent
+	End         time.Time // last attributed assistant message
+	Duration    time.Duration
+	Calls       int // assistant messages (API calls)
+	Totals
+}
+
+// SessionPrompts holds the most expensive prompts of one session.
+type SessionPrompts struct {
+	SessionID   string
+	ProjectName string
+	LastActive  time.Time
+	PromptCount int
+	TotalCost   float64      // of every prompt in the session
+	Prompts     []PromptCost // most expensive first
+}
+
+// AggregatePrompts attributes entries to prompts, most expensive first.
+// See PromptAccumulator for how entries are attributed.
+func AggregatePrompts(entries []UsageEntry, prompts []Prompt) []PromptCost {
+	acc := NewPromptAccumulator()
+	for _, p := range prompts {
+		acc.AddPrompt(p)
+	}
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Prompts()
+}
+
+// PromptAccumulator builds prompt costs one entry at a time. An entry
+// belongs to the prompt in its PromptID; an entry whose chain could not be
+// followed (a log read in pieces, or a subagent transcript) belongs to the
+// latest earlier prompt of its session. Entries of sessions without any
+// known prompt are dropped, so prompts must be added before entries.
+type PromptAccumulator struct {
+	prompts    map[string]*PromptCost
+	sessions   map[string][]Prompt // sorted by Timestamp in Prompts
+	unresolved []UsageEntry
+}
+
+// NewPromptAccumulator returns an empty accumulator.
+func NewPromptAccumulator() *PromptAccumulator {
+	return &PromptAccumulator{
+		prompts:  make(map[string]*PromptCost),
+		sessions: make(map[string][]Prompt),
+	}
+}
+
+// AddPrompt registers a prompt. A prompt seen before is ignored.
+func (a *PromptAccumulator) AddPrompt(p Prompt) {
+	if _, ok := a.prompts[p.ID]; ok || p.ID == "" {
+		return
+	}
+	a.prompts[p.ID] = &PromptCost{
+		PromptID:  p.ID,
+		SessionID: p.SessionID,
+		Preview:   p.Text,
+		Start:     p.Timestamp,
+	}
+	a.sessions[p.SessionID] = append(a.sessions[p.SessionID], p)
+}
+
+// Add accumulates one entry.
+func (a *PromptAccumulator) Add(e UsageEntry) {
+	if pc, ok := a.prompts[e.PromptID]; ok {
+		pc.add(e)
+		return
+	}
+	if len(a.sessions[e.SessionID]) > 0 {
+		a.unresolved = append(a.unresolved, e)
+	}
+}
+
+func (pc *PromptCost) add(e UsageEntry) {
+	pc.Calls++
+	pc.Add(e)
+	if e.Timestamp.After(pc.End) {
+		pc.End = e.Timestamp
+	}
+	if pc.ProjectName == "" {
+		pc.ProjectName = e.ProjectName
+	}
+}
+
+// Prompts returns the prompts with at least one call, most expensive
+// first (ties broken by start time).
+func (a *PromptAccumulator) Prompts() []PromptCost {
+	for id, ps := range a.sessions {
+		sort.Slice(ps, func(i, j int) bool { return ps[i].Timestamp.Before(ps[j].Timestamp) })
+		a.sessions[id] = ps
+	}
+	for _, e := range a.unresolved {
+		ps := a.sessions[e.SessionID]
+		i := sort.Search(len(ps), func(i int) bool { return ps[i].Timestamp.After(e.Timestamp) })
+		if i > 0 {
+			a.prompts[ps[i-1].ID].add(e)
+		}
+	}
+	a.unresolved = nil
+
+	result := make([]PromptCost, 0, len(a.prompts))
+	for _, pc := range a.prompts {
+		if pc.Calls == 0 {
+			continue
+		}
+		p := *pc
+		p.Duration = p.End.Sub(p.Start)
+		if p.Duration < 0 {
+			p.Duration = 0
+		}
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalCost != result[j].TotalCost {
+			return result[i].TotalCost > result[j].TotalCost
+		}
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// TopPromptsBySession groups prompt costs by session and keeps the n most
+// expensive prompts of each (all if n <= 0). Sessions are ordered by last
+// activity, newest first.
+func TopPromptsBySession(prompts []PromptCost, n int) []SessionPrompts {
+	bySession := make(map[string]*SessionPrompts)
+	var order []string
+	for _, p := range prompts { // most expensive first
+		s, ok := bySession[p.SessionID]
+		if !ok {
+			s = &SessionPrompts{SessionID: p.SessionID}
+			bySession[p.SessionID] = s
+			order = append(order, p.SessionID)
+		}
+		s.PromptCount++
+		s.TotalCost += p.TotalCost
+		if p.End.After(s.LastActive) {
+			s.LastActive = p.End
+		}
+		if s.ProjectName == "" {
+			s.ProjectName = p.ProjectName
+		}
+		if n <= 0 || len(s.Prompts) < n {
+			s.Prompts = append(s.Prompts, p)
+		}
+	}
+
+	result := make([]SessionPrompts, 0, len(order))
+	for _, id := range order {
+		result = append(result, *bySession[id])
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastActive.Equal(result[j].LastActive) {
+			return result[i].LastActive.After(result[j].LastActive)
+		}
+		return result[i].SessionID < result[j].SessionID
+	})
+	return result
+}
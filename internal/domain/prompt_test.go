@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregatePrompts(t *testing.T) {
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	prompts := []Prompt{
+		{ID: "p1", SessionID: "s1", Timestamp: base, Text: "first"},
+		{ID: "p2", SessionID: "s1", Timestamp: base.Add(time.Hour), Text: "second"},
+		{ID: "p2", SessionID: "s1", Timestamp: base.Add(time.Hour), Text: "second"}, // re-read copy
+	}
+	entries := []UsageEntry{
+		{Timestamp: base.Add(time.Minute), SessionID: "s1", PromptID: "p1", InputTokens: 100, CostUSD: 1.0},
+		{Timestamp: base.Add(10 * time.Minute), SessionID: "s1", PromptID: "p1", InputTokens: 100, CostUSD: 1.0},
+		{Timestamp: base.Add(61 * time.Minute), SessionID: "s1", PromptID: "p2", InputTokens: 10, CostUSD: 0.5},
+		// Chain not followed: attributed to the latest earlier prompt.
+		{Timestamp: base.Add(20 * time.Minute), SessionID: "s1", InputTokens: 100, CostUSD: 1.0},
+		// No earlier prompt, or no prompts for the session: dropped.
+		{Timestamp: base.Add(-time.Minute), SessionID: "s1", CostUSD: 9.0},
+		{Timestamp: base, SessionID: "s2", CostUSD: 9.0},
+	}
+
+	result := AggregatePrompts(entries, prompts)
+	if len(result) != 2 {
+		t.Fatalf("got %d prompts, want 2", len(result))
+	}
+	p1 := result[0]
+	if p1.PromptID != "p1" || p1.Calls != 3 || p1.TotalCost != 3.0 || p1.InputTokens != 300 {
+		t.Errorf("p1 = %s/%d calls/$%.2f/%d in, want p1/3/$3.00/300", p1.PromptID, p1.Calls, p1.TotalCost, p1.InputTokens)
+	}
+	if p1.Duration != 20*time.Minute {
+		t.Errorf("p1 Duration = %v, want 20m", p1.Duration)
+	}
+	if result[1].Preview != "second" || result[1].Calls != 1 {
+		t.Errorf("p2 = %+v, want 1 call", result[1])
+	}
+}
+
+func TestTopPromptsBySession(t *testing.T) {
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	costs := []PromptCost{ // most expensive first, as AggregatePrompts returns them
+		{PromptID: "a", SessionID: "old", Totals: Totals{TotalCost: 5}, End: base},
+		{PromptID: "b", SessionID: "new", Totals: Totals{TotalCost: 3}, End: base.Add(time.Hour)},
+		{PromptID: "c", SessionID: "old", Totals: Totals{TotalCost: 2}, End: base},
+		{PromptID: "d", SessionID: "new", Totals: Totals{TotalCost: 1}, End: base.Add(2 * time.Hour)},
+		{PromptID: "e", SessionID: "new", Totals: Totals{TotalCost: 0.5}, End: base.Add(time.Hour)},
+	}
+
+	sessions := TopPromptsBySession(costs, 2)
+	if len(sessions) != 2 || sessions[0].SessionID != "new" {
+		t.Fatalf("sessions = %+v, want newest first", sessions)
+	}
+	s := sessions[0]
+	if s.PromptCount != 3 || s.TotalCost != 4.5 || len(s.Prompts) != 2 || s.Prompts[0].PromptID != "b" {
+		t.Errorf("new = %d prompts/$%.2f/%d kept, want 3/$4.50/2 starting with b", s.PromptCount, s.TotalCost, len(s.Prompts))
+	}
+}
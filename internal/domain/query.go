@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dimension names a key a query groups entries by.
+type Dimension string
+
+const (
+	DimHour    Dimension = "hour"    // "2006-01-02 15:00"
+	DimDay     Dimension = "day"     // "2006-01-02"
+	DimWeek    Dimension = "week"    // date of the Monday starting the week
+	DimMonth   Dimension = "month"   // "2006-01"
+	DimModel   Dimension = "model"   // model name
+	DimProject Dimension = "project" // project display name
+	DimSession Dimension = "session" // session ID
+	DimBranch  Dimension = "branch"  // git branch
+	DimSource  Dimension = "source"  // Claude data directory
+)
+
+// Dimensions lists every dimension a query accepts, in documentation order.
+var Dimensions = []Dimension{DimHour, DimDay, DimWeek, DimMonth, DimModel, DimProject, DimSession, DimBranch, DimSource}
+
+// Metric names a value a query computes for each group.
+type Metric string
+
+const (
+	MetricInput       Metric = "input"
+	MetricOutput      Metric = "output"
+	MetricCacheCreate Metric = "cache_create"
+	MetricCacheRead   Metric = "cache_read"
+	MetricTokens      Metric = "tokens" // all token types
+	MetricCost        Metric = "cost"   // USD
+	MetricCalls       Metric = "calls"  // API calls (entries)
+	MetricCacheHit    Metric = "cache_hit"
+)
+
+// Metrics lists every metric a query accepts, in documentation order.
+var Metrics = []Metric{MetricInput, MetricOutput, MetricCacheCreate, MetricCacheRead, MetricTokens, MetricCost, MetricCalls, MetricCacheHit}
+
+// Query groups entries by any combination of dimensions and computes the
+// selected metrics for each group.
+type Query struct {
+	GroupBy  []Dimension
+	Metrics  []Metric
+	SortBy   Metric // "" orders rows by their keys
+	Limit    int    // 0 keeps every row
+	Location *time.Location
+}
+
+// ParseQuery builds a query from comma-separated dimension and metric
+// names. Time dimensions are bucketed in tz.
+func ParseQuery(groupBy, metrics string, tz *time.Location) (Query, error) {
+	q := Query{Location: tz}
+	for _, name := range splitNames(groupBy) {
+		d := Dimension(name)
+		if !containsName(Dimensions, d) {
+			return Query{}, fmt.Errorf("unknown dimension %q (use %s)", name, JoinNames(Dimensions))
+		}
+		q.GroupBy = append(q.GroupBy, d)
+	}
+	for _, name := range splitNames(metrics) {
+		m, err := ParseMetric(name)
+		if err != nil {
+			return Query{}, err
+		}
+		q.Metrics = append(q.Metrics, m)
+	}
+	if len(q.Metrics) == 0 {
+		q.Metrics = []Metric{MetricTokens, MetricCost}
+	}
+	return q, nil
+}
+
+// ParseMetric returns the metric called name.
+func ParseMetric(name string) (Metric, error) {
+	m := Metric(strings.ToLower(strings.TrimSpace(name)))
+	if !containsName(Metrics, m) {
+		return "", fmt.Errorf("unknown metric %q (use %s)", name, JoinNames(Metrics))
+	}
+	return m, nil
+}
+
+func splitNames(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func containsName[T ~string](names []T, name T) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JoinNames returns names separated by commas, for usage and error
+// messages.
+func JoinNames[T ~string](names []T) string {
+	parts := make([]string, len(names))
+	for i, n := range names {
+		parts[i] = string(n)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// key returns the value of dimension d for an entry.
+func (q Query) key(d Dimension, e UsageEntry) string {
+	t := e.Timestamp.In(q.Location)
+	switch d {
+	case DimHour:
+		return t.Format("2006-01-02 15:00")
+	case DimDay:
+		return t.Format("2006-01-02")
+	case DimWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		return t.AddDate(0, 0, -offset).Format("2006-01-02")
+	case DimMonth:
+		return t.Format("2006-01")
+	case DimModel:
+		return e.Model
+	case DimProject:
+		return KeyProject(e)
+	case DimSession:
+		return e.SessionID
+	case DimBranch:
+		return e.GitBranch
+	case DimSource:
+		return e.Source
+	}
+	return ""
+}
+
+// QueryRow is one group of a query result.
+type QueryRow struct {
+	Keys   []string  // one value per GroupBy dimension
+	Values []float64 // one value per metric
+}
+
+// QueryResult holds the rows of a query along with its columns.
+type QueryResult struct {
+	GroupBy []Dimension
+	Metrics []Metric
+	Rows    []QueryRow
+}
+
+// RunQuery evaluates q over entries.
+func RunQuery(entries []UsageEntry, q Query) QueryResult {
+	acc := NewQueryAccumulator(q)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Result()
+}
+
+// QueryAccumulator evaluates a query one entry at a time, holding one set
+// of totals per group.
+type QueryAccumulator struct {
+	q      Query
+	groups map[string]*queryGroup
+}
+
+type queryGroup struct {
+	keys []string
+	Totals
+	calls int
+}
+
+// NewQueryAccumulator returns an empty accumulator for q.
+func NewQueryAccumulator(q Query) *QueryAccumulator {
+	if q.Location == nil {
+		q.Location = time.UTC
+	}
+	return &QueryAccumulator{q: q, groups: make(map[string]*queryGroup)}
+}
+
+// Add accumulates one entry.
+func (a *QueryAccumulator) Add(e UsageEntry) {
+	keys := make([]string, len(a.q.GroupBy))
+	for i, d := range a.q.GroupBy {
+		keys[i] = a.q.key(d, e)
+	}
+	id := strings.Join(keys, "\x00")
+	g, ok := a.groups[id]
+	if !ok {
+		g = &queryGroup{keys: keys}
+		a.groups[id] = g
+	}
+	g.Add(e)
+	g.calls++
+}
+
+// value computes metric m of a group.
+func (g *queryGroup) value(m Metric) float64 {
+	switch m {
+	case MetricInput:
+		return float64(g.InputTokens)
+	case MetricOutput:
+		return float64(g.OutputTokens)
+	case MetricCacheCreate:
+		return float64(g.CacheCreationTokens)
+	case MetricCacheRead:
+		return float64(g.CacheReadTokens)
+	case MetricTokens:
+		return float64(g.TotalTokens())
+	case MetricCost:
+		return g.TotalCost
+	case MetricCalls:
+		return float64(g.calls)
+	case MetricCacheHit:
+		return g.CacheHitRatio()
+	}
+	return 0
+}
+
+// Result returns the rows ordered by their keys, or by the sort metric
+// descending when one is set, cut to the limit.
+func (a *QueryAccumulator) Result() QueryResult {
+	res := QueryResult{GroupBy: a.q.GroupBy, Metrics: a.q.Metrics, Rows: make([]QueryRow, 0, len(a.groups))}
+	groups := make([]*queryGroup, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if a.q.SortBy != "" {
+			vi, vj := groups[i].value(a.q.SortBy), groups[j].value(a.q.SortBy)
+			if vi != vj {
+				return vi > vj
+			}
+		}
+		ki, kj := groups[i].keys, groups[j].keys
+		for k := range ki {
+			if ki[k] != kj[k] {
+				return ki[k] < kj[k]
+			}
+		}
+		return false
+	})
+	if a.q.Limit > 0 && len(groups) > a.q.Limit {
+		groups = groups[:a.q.Limit]
+	}
+	for _, g := range groups {
+		row := QueryRow{Keys: g.keys, Values: make([]float64, len(a.q.Metrics))}
+		for i, m := range a.q.Metrics {
+			row.Values[i] = g.value(m)
+		}
+		res.Rows = append(res.Rows, row)
+	}
+	return res
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("Day, model", "cost,output", time.UTC)
+	if err != nil {
+		t.Fatalf("ParseQuery: %v", err)
+	}
+	if len(q.GroupBy) != 2 || q.GroupBy[0] != DimDay || q.GroupBy[1] != DimModel {
+		t.Errorf("GroupBy = %v, want [day model]", q.GroupBy)
+	}
+	if len(q.Metrics) != 2 || q.Metrics[0] != MetricCost || q.Metrics[1] != MetricOutput {
+		t.Errorf("Metrics = %v, want [cost output]", q.Metrics)
+	}
+
+	if q, _ := ParseQuery("", "", time.UTC); len(q.Metrics) != 2 {
+		t.Errorf("default metrics = %v, want [tokens cost]", q.Metrics)
+	}
+	if _, err := ParseQuery("planet", "", time.UTC); err == nil {
+		t.Error("ParseQuery accepted an unknown dimension")
+	}
+	if _, err := ParseQuery("", "joy", time.UTC); err == nil {
+		t.Error("ParseQuery accepted an unknown metric")
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	tz := time.FixedZone("UTC-5", -5*3600)
+	entries := []UsageEntry{
+		// 2026-03-02 22:00 in tz
+		{Timestamp: time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC), Model: "opus", InputTokens: 100, CacheReadTokens: 300, CostUSD: 2},
+		{Timestamp: time.Date(2026, 3, 3, 4, 0, 0, 0, time.UTC), Model: "opus", OutputTokens: 50, CostUSD: 1},
+		{Timestamp: time.Date(2026, 3, 3, 4, 0, 0, 0, time.UTC), Model: "haiku", OutputTokens: 10, CostUSD: 0.1},
+		// 2026-03-03 in tz
+		{Timestamp: time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC), Model: "opus", OutputTokens: 5, CostUSD: 4},
+	}
+
+	q := Query{
+		GroupBy:  []Dimension{DimDay, DimModel},
+		Metrics:  []Metric{MetricCost, MetricOutput, MetricCalls, MetricCacheHit},
+		Location: tz,
+	}
+	res := RunQuery(entries, q)
+	if len(res.Rows) != 3 {
+		t.Fatalf("rows = %d, want 3", len(res.Rows))
+	}
+	first := res.Rows[0]
+	if first.Keys[0] != "2026-03-02" || first.Keys[1] != "haiku" {
+		t.Errorf("first row keys = %v, want [2026-03-02 haiku]", first.Keys)
+	}
+	opus := res.Rows[1]
+	if opus.Values[0] != 3 || opus.Values[1] != 50 || opus.Values[2] != 2 {
+		t.Errorf("opus on 03-02 = %v, want cost 3, output 50, calls 2", opus.Values)
+	}
+	if opus.Values[3] != 0.75 {
+		t.Errorf("cache hit = %f, want 0.75", opus.Values[3])
+	}
+
+	q.GroupBy = []Dimension{DimWeek}
+	q.SortBy = MetricCost
+	if res := RunQuery(entries, q); len(res.Rows) != 1 || res.Rows[0].Keys[0] != "2026-03-02" {
+		t.Errorf("week rows = %v, want one week starting 2026-03-02", res.Rows)
+	}
+
+	q.GroupBy = []Dimension{DimModel}
+	q.Limit = 1
+	res = RunQuery(entries, q)
+	if len(res.Rows) != 1 || res.Rows[0].Keys[0] != "opus" {
+		t.Errorf("top model = %v, want opus only", res.Rows)
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// SessionAggregate holds usage totals for one Claude Code conversation.
+type SessionAggregate struct {
+	SessionID     string
+	ProjectPath   string
+	ProjectName   string
+	FirstActivity time.Time
+	LastActivity  time.Time
+	Duration      time.Duration // LastActivity - FirstActivity
+	Totals
+	MessageCount int
+	Models       map[string]ModelBreakdown
+}
+
+// AggregateBySession groups entries by session, most recently active
+// first. Entries without a session ID are skipped.
+func AggregateBySession(entries []UsageEntry) []SessionAggregate {
+	acc := NewSessionAccumulator()
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Sessions()
+}
+
+// SessionAccumulator builds session aggregates one entry at a time, in
+// any order.
+type SessionAccumulator struct {
+	sessions map[string]*SessionAggregate
+}
+
+// NewSessionAccumulator returns an empty accumulator.
+func NewSessionAccumulator() *SessionAccumulator {
+	return &SessionAccumulator{sessions: make(map[string]*SessionAggregate)}
+}
+
+// Add accumulates one entry.
+func (a *SessionAccumulator) Add(e UsageEntry) {
+	if e.SessionID == "" {
+		return
+	}
+	s, ok := a.sessions[e.SessionID]
+	if !ok {
+		s = &SessionAggregate{
+			SessionID:     e.SessionID,
+			FirstActivity: e.Timestamp,
+			LastActivity:  e.Timestamp,
+			Models:        make(map[string]ModelBreakdown),
+		}
+		a.sessions[e.SessionID] = s
+	}
+	if e.Timestamp.Before(s.FirstActivity) {
+		s.FirstActivity = e.Timestamp
+	}
+	if e.Timestamp.After(s.LastActivity) {
+		s.LastActivity = e.Timestamp
+	}
+	if s.ProjectPath == "" {
+		s.ProjectPath, s.ProjectName = e.ProjectPath, e.ProjectName
+	}
+	s.Add(e)
+	s.MessageCount++
+	addModel(s.Models, e)
+}
+
+// Sessions returns the aggregates, most recently active first (ties
+// broken by session ID).
+func (a *SessionAccumulator) Sessions() []SessionAggregate {
+	result := make([]SessionAggregate, 0, len(a.sessions))
+	for _, s := range a.sessions {
+		agg := *s
+		agg.Duration = agg.LastActivity.Sub(agg.FirstActivity)
+		total := agg.TotalTokens()
+		agg.Models = make(map[string]ModelBreakdown, len(s.Models))
+		for k, mb := range s.Models {
+			if total > 0 {
+				mb.Percentage = float64(mb.Tokens) / float64(total) * 100
+			}
+			agg.Models[k] = mb
+		}
+		result = append(result, agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastActivity.Equal(result[j].LastActivity) {
+			return result[i].LastActivity.After(result[j].LastActivity)
+		}
+		return result[i].SessionID < result[j].SessionID
+	})
+	return result
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateBySession(t *testing.T) {
+	base := time.Date(2026, 2, 21, 10, 0, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{SessionID: "a", ProjectPath: "/src/app", ProjectName: "app", Timestamp: base.Add(time.Hour), InputTokens: 300, CostUSD: 3, Model: "opus"},
+		{SessionID: "b", ProjectName: "lib", Timestamp: base.Add(30 * time.Minute), InputTokens: 50, CostUSD: 0.5, Model: "haiku"},
+		{SessionID: "a", ProjectPath: "/src/app", ProjectName: "app", Timestamp: base, InputTokens: 100, CostUSD: 1, Model: "haiku"},
+		{Timestamp: base.Add(2 * time.Hour), InputTokens: 999}, // no session
+	}
+
+	sessions := AggregateBySession(entries)
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	a := sessions[0]
+	if a.SessionID != "a" {
+		t.Fatalf("sessions[0] = %q, want the most recently active session a", a.SessionID)
+	}
+	if !a.FirstActivity.Equal(base) || !a.LastActivity.Equal(base.Add(time.Hour)) || a.Duration != time.Hour {
+		t.Errorf("session a = %v - %v (%v), want %v - %v (1h)", a.FirstActivity, a.LastActivity, a.Duration, base, base.Add(time.Hour))
+	}
+	if a.MessageCount != 2 || a.TotalTokens() != 400 || a.TotalCost != 4 || a.ProjectName != "app" {
+		t.Errorf("session a = %d msgs, %d tokens, $%.0f, %q; want 2, 400, $4, app", a.MessageCount, a.TotalTokens(), a.TotalCost, a.ProjectName)
+	}
+	if p := a.Models["opus"].Percentage; p != 75 {
+		t.Errorf("session a opus share = %.1f, want 75", p)
+	}
+	if sessions[1].SessionID != "b" || sessions[1].Duration != 0 {
+		t.Errorf("sessions[1] = %q lasting %v, want b lasting 0", sessions[1].SessionID, sessions[1].Duration)
+	}
+}
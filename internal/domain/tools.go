@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ToolUsage counts the invocations of one tool. Tokens and Cost are those
+// of the assistant turns that invoked the tool; a turn calling several
+// tools counts toward each of them, so they do not add up to a total.
+type ToolUsage struct {
+	Name   string
+	Calls  int // tool_use blocks
+	Turns  int // assistant messages with at least one call
+	Tokens int
+	Cost   float64
+}
+
+// ToolGroup holds the tool usage of entries sharing a key, such as a
+// session, project or day.
+type ToolGroup struct {
+	Key   string
+	Calls int
+	Turns int         // assistant messages calling any tool
+	Tools []ToolUsage // most calls first
+}
+
+// addTools accumulates the tool calls of an entry into m, which maps tool
+// names to their usage, and returns m (allocated on first use).
+func addTools(m map[string]ToolUsage, e UsageEntry) map[string]ToolUsage {
+	if len(e.Tools) == 0 {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]ToolUsage)
+	}
+	for i, name := range e.Tools {
+		tu := m[name]
+		tu.Name = name
+		tu.Calls++
+		if !containsTool(e.Tools[:i], name) {
+			tu.Turns++
+			tu.Tokens += e.TotalTokens()
+			tu.Cost += e.CostUSD
+		}
+		m[name] = tu
+	}
+	return m
+}
+
+func containsTool(tools []string, name string) bool {
+	for _, t := range tools {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SortedTools returns the usage in m, most calls first (ties broken by
+// name).
+func SortedTools(m map[string]ToolUsage) []ToolUsage {
+	tools := make([]ToolUsage, 0, len(m))
+	for _, tu := range m {
+		tools = append(tools, tu)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Calls != tools[j].Calls {
+			return tools[i].Calls > tools[j].Calls
+		}
+		return tools[i].Name < tools[j].Name
+	})
+	return tools
+}
+
+// Grouping keys for tool usage. KeyAll puts every entry in one group.
+var (
+	KeyAll     KeyFunc = func(UsageEntry) string { return "all" }
+	KeySession KeyFunc = func(e UsageEntry) string { return e.SessionID }
+	KeyProject KeyFunc = func(e UsageEntry) string {
+		if e.ProjectName != "" {
+			return e.ProjectName
+		}
+		return e.ProjectPath
+	}
+)
+
+// KeyDay groups entries by date ("2006-01-02") in tz.
+func KeyDay(tz *time.Location) KeyFunc {
+	return func(e UsageEntry) string { return e.Timestamp.In(tz).Format("2006-01-02") }
+}
+
+// AggregateTools groups the tool calls of entries by key, most calls
+// first. Entries without tool calls are ignored.
+func AggregateTools(entries []UsageEntry, key KeyFunc) []ToolGroup {
+	acc := NewToolAccumulator(key)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Groups()
+}
+
+// ToolAccumulator builds tool groups one entry at a time, holding one
+// total per key and tool.
+type ToolAccumulator struct {
+	key    KeyFunc
+	groups map[string]*toolTotals
+}
+
+type toolTotals struct {
+	turns int
+	tools map[string]ToolUsage
+}
+
+// NewToolAccumulator returns an empty accumulator grouping by key.
+func NewToolAccumulator(key KeyFunc) *ToolAccumulator {
+	return &ToolAccumulator{key: key, groups: make(map[string]*toolTotals)}
+}
+
+// Add accumulates one entry.
+func (a *ToolAccumulator) Add(e UsageEntry) {
+	if len(e.Tools) == 0 {
+		return
+	}
+	k := a.key(e)
+	g, ok := a.groups[k]
+	if !ok {
+		g = &toolTotals{}
+		a.groups[k] = g
+	}
+	g.turns++
+	g.tools = addTools(g.tools, e)
+}
+
+// Groups returns the groups sorted by calls descending (ties broken by
+// key).
+func (a *ToolAccumulator) Groups() []ToolGroup {
+	result := make([]ToolGroup, 0, len(a.groups))
+	for k, g := range a.groups {
+		tg := ToolGroup{Key: k, Turns: g.turns, Tools: SortedTools(g.tools)}
+		for _, tu := range tg.Tools {
+			tg.Calls += tu.Calls
+		}
+		result = append(result, tg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Calls != result[j].Calls {
+			return result[i].Calls > result[j].Calls
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateTools(t *testing.T) {
+	utc := time.UTC
+	entries := []UsageEntry{
+		{Timestamp: time.Date(2026, 2, 1, 10, 0, 0, 0, utc), SessionID: "s1", InputTokens: 100, CostUSD: 1.0, Tools: []string{"Read", "Read", "Bash"}},
+		{Timestamp: time.Date(2026, 2, 1, 11, 0, 0, 0, utc), SessionID: "s1", InputTokens: 50, CostUSD: 0.5, Tools: []string{"Read"}},
+		{Timestamp: time.Date(2026, 2, 2, 10, 0, 0, 0, utc), SessionID: "s2", InputTokens: 10, CostUSD: 0.1, Tools: []string{"Edit"}},
+		{Timestamp: time.Date(2026, 2, 2, 11, 0, 0, 0, utc), SessionID: "s2", InputTokens: 999, CostUSD: 9.9}, // no tools
+	}
+
+	groups := AggregateTools(entries, KeySession)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	s1 := groups[0]
+	if s1.Key != "s1" || s1.Calls != 4 || s1.Turns != 2 {
+		t.Errorf("s1 = %s/%d calls/%d turns, want s1/4/2", s1.Key, s1.Calls, s1.Turns)
+	}
+	read := s1.Tools[0]
+	if read.Name != "Read" || read.Calls != 3 || read.Turns != 2 {
+		t.Errorf("Read = %+v, want 3 calls in 2 turns", read)
+	}
+	if read.Tokens != 150 || read.Cost != 1.5 {
+		t.Errorf("Read tokens/cost = %d/%.2f, want 150/1.50", read.Tokens, read.Cost)
+	}
+	if bash := s1.Tools[1]; bash.Name != "Bash" || bash.Tokens != 100 {
+		t.Errorf("Bash = %+v, want 100 tokens", bash)
+	}
+
+	days := AggregateTools(entries, KeyDay(utc))
+	if len(days) != 2 || days[1].Key != "2026-02-02" || days[1].Calls != 1 {
+		t.Errorf("days = %+v, want 2026-02-02 with 1 call last", days)
+	}
+}
+
+func TestBlockTools(t *testing.T) {
+	base := time.Date(2026, 2, 1, 10, 0, 0, 0, time.UTC)
+	entries := []UsageEntry{
+		{Timestamp: base, InputTokens: 10, Tools: []string{"Bash"}},
+		{Timestamp: base.Add(2 * time.Hour), InputTokens: 20, Tools: []string{"Bash", "Task"}},
+	}
+
+	acc := NewBlockAccumulator(BlockOptions{})
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	for _, b := range [][]SessionBlock{BuildBlocks(entries, BlockOptions{}), acc.Blocks()} {
+		if len(b) != 1 {
+			t.Fatalf("got %d blocks, want 1", len(b))
+		}
+		if bash := b[0].Tools["Bash"]; bash.Calls != 2 || bash.Tokens != 30 {
+			t.Errorf("Bash = %+v, want 2 calls and 30 tokens", bash)
+		}
+		if task := b[0].Tools["Task"]; task.Calls != 1 {
+			t.Errorf("Task calls = %d, want 1", task.Calls)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+// Totals holds the token and cost sums every aggregate reports. Aggregates
+// embed it, so entries are summed the same way everywhere.
+type Totals struct {
+	InputTokens         int
+	OutputTokens        int
+	CacheCreationTokens int
+	CacheReadTokens     int
+	TotalCost           float64
+}
+
+// Add accumulates one entry.
+func (t *Totals) Add(e UsageEntry) {
+	t.InputTokens += e.InputTokens
+	t.OutputTokens += e.OutputTokens
+	t.CacheCreationTokens += e.CacheCreationTokens
+	t.CacheReadTokens += e.CacheReadTokens
+	t.TotalCost += e.CostUSD
+}
+
+// Merge accumulates the sums of o.
+func (t *Totals) Merge(o Totals) {
+	t.InputTokens += o.InputTokens
+	t.OutputTokens += o.OutputTokens
+	t.CacheCreationTokens += o.CacheCreationTokens
+	t.CacheReadTokens += o.CacheReadTokens
+	t.TotalCost += o.TotalCost
+}
+
+// TotalTokens returns the sum of all token types.
+func (t Totals) TotalTokens() int {
+	return t.InputTokens + t.OutputTokens + t.CacheCreationTokens + t.CacheReadTokens
+}
+
+// PromptTokens returns the input tokens, whether uncached, written to the
+// cache or read from it.
+func (t Totals) PromptTokens() int {
+	return t.InputTokens + t.CacheCreationTokens + t.CacheReadTokens
+}
+
+// CacheHitRatio returns the share of prompt tokens served from the cache.
+func (t Totals) CacheHitRatio() float64 {
+	if prompt := t.PromptTokens(); prompt > 0 {
+		return float64(t.CacheReadTokens) / float64(prompt)
+	}
+	return 0
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "testing"
+
+func TestTotals(t *testing.T) {
+	var tot Totals
+	tot.Add(UsageEntry{InputTokens: 100, OutputTokens: 50, CacheCreationTokens: 200, CacheReadTokens: 700, CostUSD: 1.5})
+	tot.Merge(Totals{InputTokens: 100, TotalCost: 0.5})
+
+	if tot.TotalTokens() != 1150 {
+		t.Errorf("TotalTokens() = %d, want 1150", tot.TotalTokens())
+	}
+	if tot.PromptTokens() != 1100 {
+		t.Errorf("PromptTokens() = %d, want 1100", tot.PromptTokens())
+	}
+	if tot.TotalCost != 2 {
+		t.Errorf("TotalCost = %v, want 2", tot.TotalCost)
+	}
+	if got := tot.CacheHitRatio(); got < 0.636 || got > 0.637 {
+		t.Errorf("CacheHitRatio() = %v, want 700/1100", got)
+	}
+	if (Totals{}).CacheHitRatio() != 0 {
+		t.Error("CacheHitRatio() of empty totals should be 0")
+	}
+}
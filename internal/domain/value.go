@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// ValueOptions sets the plan a value report compares usage with.
+type ValueOptions struct {
+	Plan       string  // display name
+	Price      float64 // fee per billing cycle in USD
+	BillingDay int     // day of the month a billing cycle starts; 0 = 1
+	Location   *time.Location
+	Now        time.Time
+}
+
+// ProjectValue is one project's part of a cycle's API-equivalent cost.
+type ProjectValue struct {
+	Project string
+	Cost    float64
+	Share   float64 // of the cycle's cost, and so of the value received
+}
+
+// CycleValue compares the API-equivalent cost of one billing cycle with
+// the fee.
+type CycleValue struct {
+	Start     time.Time
+	End       time.Time
+	Current   bool    // the cycle containing Now
+	Cost      float64 // API-equivalent USD
+	Fee       float64
+	Savings   float64   // Cost - Fee; negative when the plan cost more
+	Multiple  float64   // Cost / Fee, 0 without a fee
+	BreakEven time.Time `json:",omitzero"` // day the cost reached the fee
+	Day       int       // day of the cycle BreakEven fell on, 1-based; 0 if not reached
+	Projects  []ProjectValue
+}
+
+// ValueReport compares usage with the plan fee, cycle by cycle.
+type ValueReport struct {
+	Plan     string
+	Price    float64
+	Cycles   []CycleValue // newest first
+	Cost     float64      // over all cycles
+	Fees     float64
+	Savings  float64
+	Multiple float64
+}
+
+// BuildValueReport builds a value report from entries.
+func BuildValueReport(entries []UsageEntry, opts ValueOptions) ValueReport {
+	acc := NewValueAccumulator(opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Report()
+}
+
+// ValueAccumulator builds a value report one entry at a time, holding the
+// cost of each day and project per cycle.
+type ValueAccumulator struct {
+	opts   ValueOptions
+	cycles map[time.Time]*cycleUsage // by cycle start
+}
+
+type cycleUsage struct {
+	end      time.Time
+	days     map[time.Time]float64 // cost by day start
+	projects map[string]float64
+}
+
+// NewValueAccumulator returns an empty accumulator.
+func NewValueAccumulator(opts ValueOptions) *ValueAccumulator {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return &ValueAccumulator{opts: opts, cycles: make(map[time.Time]*cycleUsage)}
+}
+
+// Add accumulates one entry.
+func (a *ValueAccumulator) Add(e UsageEntry) {
+	t := e.Timestamp.In(a.opts.Location)
+	start, end := BillingCycle(t, a.opts.BillingDay)
+	c, ok := a.cycles[start]
+	if !ok {
+		c = &cycleUsage{end: end, days: make(map[time.Time]float64), projects: make(map[string]float64)}
+		a.cycles[start] = c
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, a.opts.Location)
+	c.days[day] += e.CostUSD
+	c.projects[KeyProject(e)] += e.CostUSD
+}
+
+// Report returns the cycles with usage, newest first, with each cycle's
+// projects by cost.
+func (a *ValueAccumulator) Report() ValueReport {
+	r := ValueReport{Plan: a.opts.Plan, Price: a.opts.Price, Cycles: make([]CycleValue, 0, len(a.cycles))}
+	for start, c := range a.cycles {
+		cv := CycleValue{
+			Start:   start,
+			End:     c.end,
+			Current: !a.opts.Now.Before(start) && a.opts.Now.Before(c.end),
+			Fee:     a.opts.Price,
+		}
+		days := make([]time.Time, 0, len(c.days))
+		for d, cost := range c.days {
+			days = append(days, d)
+			cv.Cost += cost
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		var running float64
+		for _, d := range days {
+			running += c.days[d]
+			if cv.Fee > 0 && running >= cv.Fee {
+				cv.BreakEven = d
+				// Calendar days, so a DST change does not shift the count
+				cv.Day = int(time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC).
+					Sub(time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)).Hours()/24) + 1
+				break
+			}
+		}
+		cv.Savings = cv.Cost - cv.Fee
+		if cv.Fee > 0 {
+			cv.Multiple = cv.Cost / cv.Fee
+		}
+		for p, cost := range c.projects {
+			pv := ProjectValue{Project: p, Cost: cost}
+			if cv.Cost > 0 {
+				pv.Share = cost / cv.Cost
+			}
+			cv.Projects = append(cv.Projects, pv)
+		}
+		sort.Slice(cv.Projects, func(i, j int) bool {
+			if cv.Projects[i].Cost != cv.Projects[j].Cost {
+				return cv.Projects[i].Cost > cv.Projects[j].Cost
+			}
+			return cv.Projects[i].Project < cv.Projects[j].Project
+		})
+		r.Cycles = append(r.Cycles, cv)
+		r.Cost += cv.Cost
+		r.Fees += cv.Fee
+	}
+	sort.Slice(r.Cycles, func(i, j int) bool { return r.Cycles[i].Start.After(r.Cycles[j].Start) })
+	r.Savings = r.Cost - r.Fees
+	if r.Fees > 0 {
+		r.Multiple = r.Cost / r.Fees
+	}
+	return r
+}
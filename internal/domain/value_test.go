@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBuildValueReport(t *testing.T) {
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	entries := []UsageEntry{
+		// Cycle from Feb 15: $150 by Feb 20, $250 in all
+		{Timestamp: at(2, 16, 9), ProjectName: "api", CostUSD: 100},
+		{Timestamp: at(2, 20, 9), ProjectName: "web", CostUSD: 50},
+		{Timestamp: at(3, 2, 9), ProjectName: "api", CostUSD: 100},
+		// Cycle from Mar 15: $40 so far
+		{Timestamp: at(3, 15, 0), ProjectName: "web", CostUSD: 40},
+	}
+	r := BuildValueReport(entries, ValueOptions{
+		Plan: "Max 5x", Price: 100, BillingDay: 15, Location: time.UTC, Now: at(3, 20, 12),
+	})
+
+	if len(r.Cycles) != 2 {
+		t.Fatalf("Cycles = %+v, want 2", r.Cycles)
+	}
+	cur, prev := r.Cycles[0], r.Cycles[1]
+	if !cur.Current || prev.Current {
+		t.Errorf("Current = %v, %v, want true, false", cur.Current, prev.Current)
+	}
+	if !prev.Start.Equal(at(2, 15, 0)) || !prev.End.Equal(at(3, 15, 0)) {
+		t.Errorf("previous cycle = %v – %v", prev.Start, prev.End)
+	}
+	if prev.Cost != 250 || prev.Savings != 150 || prev.Multiple != 2.5 {
+		t.Errorf("previous cycle cost, savings, multiple = %v, %v, %v, want 250, 150, 2.5", prev.Cost, prev.Savings, prev.Multiple)
+	}
+	// The fee was reached on Feb 16, the second day of the cycle
+	if !prev.BreakEven.Equal(at(2, 16, 0)) || prev.Day != 2 {
+		t.Errorf("break-even = %v (day %d), want Feb 16 (day 2)", prev.BreakEven, prev.Day)
+	}
+	if len(prev.Projects) != 2 || prev.Projects[0].Project != "api" || math.Abs(prev.Projects[0].Share-0.8) > 1e-9 {
+		t.Errorf("Projects = %+v, want api at 80%% first", prev.Projects)
+	}
+
+	if cur.Cost != 40 || cur.Savings != -60 || !cur.BreakEven.IsZero() || cur.Day != 0 {
+		t.Errorf("current cycle = %+v, want $40 and no break-even", cur)
+	}
+	if r.Cost != 290 || r.Fees != 200 || r.Multiple != 1.45 {
+		t.Errorf("totals = %v, %v, %v, want 290, 200, 1.45", r.Cost, r.Fees, r.Multiple)
+	}
+}
@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// WeekDuration is the length of the weekly rate-limit window.
+const WeekDuration = 7 * 24 * time.Hour
+
+// WeekStart returns the start of the weekly window that resets at
+// resetsAt, rounded to the hour like WindowStart.
+func WeekStart(resetsAt time.Time) time.Time {
+	return resetsAt.Round(time.Hour).Add(-WeekDuration).UTC()
+}
+
+// ObservedWindow is a rate-limit window reported by the usage API.
+type ObservedWindow struct {
+	Start time.Time
+	Peak  float64 // highest utilization seen, 0-100
+}
+
+// WeeklyWindow aggregates usage over one 7-day rate-limit window.
+type WeeklyWindow struct {
+	StartTime time.Time
+	EndTime   time.Time // StartTime + 7d, earlier if an observed window began before
+	Anchored  bool      // phase taken from an observed weekly reset
+	Totals
+	TotalTokens     int // Totals.TotalTokens(), kept as a field for JSON output
+	MessageCount    int
+	Models          map[string]ModelBreakdown
+	PeakUtilization float64 // highest API utilization observed, 0-100
+	HasUtilization  bool    // the API was seen during this window
+	Status          BlockStatus
+}
+
+// WeeklyOptions places the weekly windows. Observed windows set the phase;
+// before, between and after them windows repeat every 7 days. Without any
+// observed window, windows start at ResetHour on ResetDay in Location.
+type WeeklyOptions struct {
+	Observed  []ObservedWindow
+	ResetDay  time.Weekday
+	ResetHour int
+	Location  *time.Location
+}
+
+// window returns the weekly window containing t. observed must be sorted
+// by start.
+func (o WeeklyOptions) window(observed []ObservedWindow, t time.Time) (start, end time.Time, anchored bool) {
+	if len(observed) == 0 {
+		loc := o.Location
+		if loc == nil {
+			loc = time.UTC
+		}
+		lt := t.In(loc)
+		back := (int(lt.Weekday()) - int(o.ResetDay) + 7) % 7
+		start = time.Date(lt.Year(), lt.Month(), lt.Day()-back, o.ResetHour, 0, 0, 0, loc)
+		if start.After(t) {
+			start = start.AddDate(0, 0, -7)
+		}
+		return start.UTC(), start.AddDate(0, 0, 7).UTC(), false
+	}
+
+	// Latest observed window starting at or before t
+	i := sort.Search(len(observed), func(i int) bool { return observed[i].Start.After(t) })
+	if i == 0 {
+		first := observed[0].Start
+		weeks := (first.Sub(t) + WeekDuration - 1) / WeekDuration
+		start = first.Add(-weeks * WeekDuration)
+		return start, start.Add(WeekDuration), true
+	}
+	a := observed[i-1].Start
+	start = a.Add(t.Sub(a) / WeekDuration * WeekDuration)
+	end = start.Add(WeekDuration)
+	if i < len(observed) && observed[i].Start.Before(end) {
+		end = observed[i].Start
+	}
+	return start, end, true
+}
+
+// Window returns the start and end of the weekly window containing t.
+func (o WeeklyOptions) Window(t time.Time) (start, end time.Time) {
+	observed := append([]ObservedWindow(nil), o.Observed...)
+	sort.Slice(observed, func(i, j int) bool { return observed[i].Start.Before(observed[j].Start) })
+	start, end, _ = o.window(observed, t)
+	return start, end
+}
+
+// AggregateWeekly groups entries into weekly windows, oldest first.
+func AggregateWeekly(entries []UsageEntry, opts WeeklyOptions) []WeeklyWindow {
+	acc := NewWeeklyAccumulator(opts)
+	for _, e := range entries {
+		acc.Add(e)
+	}
+	return acc.Windows()
+}
+
+// WeeklyAccumulator builds weekly windows one entry at a time, in any
+// order.
+type WeeklyAccumulator struct {
+	opts     WeeklyOptions
+	observed []ObservedWindow // sorted by start
+	windows  map[int64]*WeeklyWindow
+}
+
+// NewWeeklyAccumulator returns an empty accumulator.
+func NewWeeklyAccumulator(opts WeeklyOptions) *WeeklyAccumulator {
+	observed := append([]ObservedWindow(nil), opts.Observed...)
+	sort.Slice(observed, func(i, j int) bool { return observed[i].Start.Before(observed[j].Start) })
+	return &WeeklyAccumulator{opts: opts, observed: observed, windows: make(map[int64]*WeeklyWindow)}
+}
+
+// Add accumulates one entry.
+func (a *WeeklyAccumulator) Add(e UsageEntry) {
+	start, end, anchored := a.opts.window(a.observed, e.Timestamp)
+	w := a.windows[start.Unix()]
+	if w == nil {
+		w = &WeeklyWindow{StartTime: start, EndTime: end, Anchored: anchored, Models: make(map[string]ModelBreakdown)}
+		a.windows[start.Unix()] = w
+	}
+	w.Add(e)
+	w.TotalTokens += e.TotalTokens()
+	w.MessageCount++
+	addModel(w.Models, e)
+}
+
+// Windows returns the weekly windows with usage, oldest first, with the
+// peak utilization of the matching observed window.
+func (a *WeeklyAccumulator) Windows() []WeeklyWindow {
+	now := time.Now().UTC()
+	result := make([]WeeklyWindow, 0, len(a.windows))
+	for _, w := range a.windows {
+		ww := *w
+		ww.Models = make(map[string]ModelBreakdown, len(w.Models))
+		for k, mb := range w.Models {
+			if ww.TotalTokens > 0 {
+				mb.Percentage = float64(mb.Tokens) / float64(ww.TotalTokens) * 100
+			}
+			ww.Models[k] = mb
+		}
+		for _, o := range a.observed {
+			if o.Start.Equal(ww.StartTime) {
+				ww.PeakUtilization, ww.HasUtilization = max(ww.PeakUtilization, o.Peak), true
+			}
+		}
+		if now.Before(ww.EndTime) {
+			ww.Status = BlockActive
+		} else {
+			ww.Status = BlockDone
+		}
+		result = append(result, ww)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartTime.Before(result[j].StartTime) })
+	return result
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregateWeekly_Configured(t *testing.T) {
+	seoul := time.FixedZone("KST", 9*3600)
+	// Resets on Wednesday 10:00 KST = Wednesday 01:00 UTC
+	opts := WeeklyOptions{ResetDay: time.Wednesday, ResetHour: 10, Location: seoul}
+	entries := []UsageEntry{
+		{Timestamp: time.Date(2026, 2, 18, 0, 30, 0, 0, time.UTC), InputTokens: 100, CostUSD: 1, Model: "opus"},  // Wed 09:30 KST, previous week
+		{Timestamp: time.Date(2026, 2, 18, 1, 30, 0, 0, time.UTC), InputTokens: 200, CostUSD: 2, Model: "opus"},  // Wed 10:30 KST
+		{Timestamp: time.Date(2026, 2, 24, 12, 0, 0, 0, time.UTC), InputTokens: 200, CostUSD: 3, Model: "haiku"}, // Tue
+	}
+
+	weeks := AggregateWeekly(entries, opts)
+	if len(weeks) != 2 {
+		t.Fatalf("got %d weeks, want 2", len(weeks))
+	}
+	wantStart := time.Date(2026, 2, 18, 1, 0, 0, 0, time.UTC)
+	if !weeks[1].StartTime.Equal(wantStart) || !weeks[1].EndTime.Equal(wantStart.Add(WeekDuration)) {
+		t.Errorf("week 1 = %v - %v, want %v + 7d", weeks[1].StartTime, weeks[1].EndTime, wantStart)
+	}
+	if !weeks[0].StartTime.Equal(wantStart.Add(-WeekDuration)) {
+		t.Errorf("week 0 start = %v, want %v", weeks[0].StartTime, wantStart.Add(-WeekDuration))
+	}
+	if weeks[1].MessageCount != 2 || weeks[1].TotalCost != 5 || weeks[1].Anchored {
+		t.Errorf("week 1 = %d msgs, $%.0f, anchored %v; want 2, $5, false", weeks[1].MessageCount, weeks[1].TotalCost, weeks[1].Anchored)
+	}
+	if p := weeks[1].Models["opus"].Percentage; p != 50 {
+		t.Errorf("week 1 opus share = %.1f, want 50", p)
+	}
+	if weeks[1].HasUtilization {
+		t.Error("week 1 has utilization without observed windows")
+	}
+}
+
+func TestAggregateWeekly_Observed(t *testing.T) {
+	observed := []ObservedWindow{
+		{Start: time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), Peak: 30},
+		{Start: time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), Peak: 45},  // same window seen again
+		{Start: time.Date(2026, 2, 20, 14, 0, 0, 0, time.UTC), Peak: 10}, // reset moved
+	}
+	opts := WeeklyOptions{Observed: observed, ResetDay: time.Monday}
+	entries := []UsageEntry{
+		{Timestamp: time.Date(2026, 2, 5, 0, 0, 0, 0, time.UTC), Model: "opus"},  // before: Feb 03 09:00
+		{Timestamp: time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC), Model: "opus"}, // Feb 10 09:00, observed
+		{Timestamp: time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC), Model: "opus"}, // Feb 17 09:00, cut at Feb 20 14:00
+		{Timestamp: time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC), Model: "opus"}, // Feb 20 14:00, observed
+		{Timestamp: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Model: "haiku"}, // Feb 27 14:00
+	}
+
+	weeks := AggregateWeekly(entries, opts)
+	want := []struct {
+		start, end time.Time
+		peak       float64
+		hasPeak    bool
+	}{
+		{time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), 0, false},
+		{time.Date(2026, 2, 10, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 17, 9, 0, 0, 0, time.UTC), 45, true},
+		{time.Date(2026, 2, 17, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 20, 14, 0, 0, 0, time.UTC), 0, false},
+		{time.Date(2026, 2, 20, 14, 0, 0, 0, time.UTC), time.Date(2026, 2, 27, 14, 0, 0, 0, time.UTC), 10, true},
+		{time.Date(2026, 2, 27, 14, 0, 0, 0, time.UTC), time.Date(2026, 3, 6, 14, 0, 0, 0, time.UTC), 0, false},
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("got %d weeks, want %d", len(weeks), len(want))
+	}
+	for i, w := range want {
+		g := weeks[i]
+		if !g.StartTime.Equal(w.start) || !g.EndTime.Equal(w.end) {
+			t.Errorf("week %d = %v - %v, want %v - %v", i, g.StartTime, g.EndTime, w.start, w.end)
+		}
+		if g.PeakUtilization != w.peak || g.HasUtilization != w.hasPeak || !g.Anchored {
+			t.Errorf("week %d peak = %.0f/%v, anchored %v; want %.0f/%v, true", i, g.PeakUtilization, g.HasUtilization, g.Anchored, w.peak, w.hasPeak)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	got := WeekStart(time.Date(2026, 2, 27, 8, 59, 41, 0, time.UTC))
+	if want := time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("WeekStart = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+// Package history remembers the rate-limit windows reported by the usage
+// API, so blocks can be anchored to the real windows long after the API
+// moved on to newer ones. It also keeps the utilization samples the 5-hour
+// cap is estimated from.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anomredux/claude-smi/internal/api"
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+// maxWindows caps the windows kept per kind, oldest dropped first. Five
+// hour windows at most four times a day cover well over a year.
+const maxWindows = 2000
+
+// maxSamples caps the utilization samples kept, oldest dropped first.
+const maxSamples = 5000
+
+// Windows holds the observed windows, ascending by start.
+type Windows struct {
+	FiveHour []domain.ObservedWindow `json:"five_hour"`
+	SevenDay []domain.ObservedWindow `json:"seven_day"`
+	Samples  []domain.CapSample      `json:"samples,omitempty"` // ascending by time
+}
+
+// Store is a JSON file of observed windows. It is safe for concurrent use.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	windows Windows
+}
+
+// New returns an empty store backed by path. Nothing is read until Load.
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the observed windows. A missing file yields none.
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read windows: %w", err)
+	}
+	var w Windows
+	if err := json.Unmarshal(data, &w); err != nil {
+		return fmt.Errorf("decode windows: %w", err)
+	}
+	s.mu.Lock()
+	s.windows = w
+	s.mu.Unlock()
+	return nil
+}
+
+// Observe records the windows in an API response and their utilization,
+// and reports whether anything was new: a window, or a higher peak.
+func (s *Store) Observe(u *api.UsageData) bool {
+	if u == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var changed, c bool
+	if t, err := u.FiveHour.ResetTime(); err == nil {
+		s.windows.FiveHour, changed = observe(s.windows.FiveHour, domain.WindowStart(t), u.FiveHour.Utilization)
+	}
+	if t, err := u.SevenDay.ResetTime(); err == nil {
+		s.windows.SevenDay, c = observe(s.windows.SevenDay, domain.WeekStart(t), u.SevenDay.Utilization)
+		changed = changed || c
+	}
+	return changed
+}
+
+// observe adds the window starting at start to the sorted windows, or
+// raises its peak.
+func observe(windows []domain.ObservedWindow, start time.Time, utilization float64) ([]domain.ObservedWindow, bool) {
+	i := sort.Search(len(windows), func(i int) bool { return !windows[i].Start.Before(start) })
+	if i < len(windows) && windows[i].Start.Equal(start) {
+		if utilization <= windows[i].Peak {
+			return windows, false
+		}
+		windows[i].Peak = utilization
+		return windows, true
+	}
+	windows = append(windows, domain.ObservedWindow{})
+	copy(windows[i+1:], windows[i:])
+	windows[i] = domain.ObservedWindow{Start: start, Peak: utilization}
+	if len(windows) > maxWindows {
+		windows = windows[len(windows)-maxWindows:]
+	}
+	return windows, true
+}
+
+// Sample records a 5-hour utilization sample and reports whether it was
+// kept. Samples without usage are dropped, as are repeats of the last
+// utilization seen in the same window: those only add the usage logged
+// since, which the utilization has not caught up with.
+func (s *Store) Sample(sample domain.CapSample) bool {
+	if sample.Tokens <= 0 || sample.Utilization <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.windows.Samples) - 1; i >= 0; i-- {
+		prev := s.windows.Samples[i]
+		if prev.Window.Equal(sample.Window) {
+			if prev.Utilization == sample.Utilization {
+				return false
+			}
+			break
+		}
+	}
+	s.windows.Samples = append(s.windows.Samples, sample)
+	if len(s.windows.Samples) > maxSamples {
+		s.windows.Samples = s.windows.Samples[len(s.windows.Samples)-maxSamples:]
+	}
+	return true
+}
+
+// Samples returns the utilization samples, ascending.
+func (s *Store) Samples() []domain.CapSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.CapSample(nil), s.windows.Samples...)
+}
+
+// FiveHour returns the observed 5-hour windows, ascending.
+func (s *Store) FiveHour() []domain.ObservedWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.ObservedWindow(nil), s.windows.FiveHour...)
+}
+
+// SevenDay returns the observed weekly windows, ascending.
+func (s *Store) SevenDay() []domain.ObservedWindow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.ObservedWindow(nil), s.windows.SevenDay...)
+}
+
+// Starts returns the start of each window.
+func Starts(windows []domain.ObservedWindow) []time.Time {
+	starts := make([]time.Time, len(windows))
+	for i, w := range windows {
+		starts[i] = w.Start
+	}
+	return starts
+}
+
+// Save writes the observed windows atomically.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.Marshal(s.windows)
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode windows: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("create data dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".windows-*")
+	if err != nil {
+		return fmt.Errorf("create temp windows: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write windows: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp windows: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replace windows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anomredux/claude-smi/internal/api"
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+func TestStore_ObserveAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "windows.json")
+
+	s := New(path)
+	if err := s.Load(); err != nil {
+		t.Fatalf("Load on missing file = %v, want nil", err)
+	}
+
+	usage := &api.UsageData{
+		FiveHour: api.WindowData{ResetsAt: "2026-02-21T15:59:30Z"},
+		SevenDay: api.WindowData{Utilization: 25, ResetsAt: "2026-02-27T09:00:12Z"},
+	}
+	if !s.Observe(usage) {
+		t.Error("first Observe = false, want true")
+	}
+	// The same windows with jittered resets_at are not new.
+	usage.FiveHour.ResetsAt = "2026-02-21T16:00:41Z"
+	if s.Observe(usage) {
+		t.Error("repeated Observe = true, want false")
+	}
+	usage.SevenDay.Utilization = 40 // a higher peak
+	if !s.Observe(usage) {
+		t.Error("Observe of a higher peak = false, want true")
+	}
+	usage.FiveHour.ResetsAt = "2026-02-21T11:00:00Z" // an earlier window
+	if !s.Observe(usage) {
+		t.Error("Observe of an earlier window = false, want true")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := New(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	five := Starts(loaded.FiveHour())
+	want := []time.Time{
+		time.Date(2026, 2, 21, 6, 0, 0, 0, time.UTC),
+		time.Date(2026, 2, 21, 11, 0, 0, 0, time.UTC),
+	}
+	if len(five) != len(want) {
+		t.Fatalf("FiveHour = %v, want %v", five, want)
+	}
+	for i := range want {
+		if !five[i].Equal(want[i]) {
+			t.Errorf("FiveHour[%d] = %v, want %v", i, five[i], want[i])
+		}
+	}
+	if week := loaded.SevenDay(); len(week) != 1 || !week[0].Start.Equal(time.Date(2026, 2, 20, 9, 0, 0, 0, time.UTC)) || week[0].Peak != 40 {
+		t.Errorf("SevenDay = %v, want [2026-02-20 09:00 at 40%%]", week)
+	}
+}
+
+func TestStore_Sample(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "windows.json")
+	s := New(path)
+	window := time.Date(2026, 2, 21, 11, 0, 0, 0, time.UTC)
+	sample := domain.CapSample{Time: window.Add(time.Hour), Window: window, Utilization: 10, Tokens: 1000, Cost: 1, Mix: "opus"}
+
+	if !s.Sample(sample) {
+		t.Error("first Sample = false, want true")
+	}
+	later := sample
+	later.Time, later.Tokens = window.Add(2*time.Hour), 1200
+	if s.Sample(later) {
+		t.Error("Sample at the same utilization = true, want false")
+	}
+	later.Utilization = 12
+	if !s.Sample(later) {
+		t.Error("Sample at a new utilization = false, want true")
+	}
+	if s.Sample(domain.CapSample{Window: window, Utilization: 15}) {
+		t.Error("Sample without usage = true, want false")
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := New(path)
+	if err := loaded.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := loaded.Samples()
+	if len(got) != 2 || got[1].Utilization != 12 || got[1].Tokens != 1200 || got[1].Mix != "opus" || !got[1].Window.Equal(window) {
+		t.Errorf("Samples = %+v, want the 10%% and 12%% samples", got)
+	}
+}
@@ -13,6 +13,12 @@ var en = map[string]string{
 	"tab_live":         "Live Dashboard",
 	"tab_blocks":       "Session Blocks",
 	"tab_daily_report": "Report",
+	"tab_tools":        "Tools",
+	"tab_prompts":      "Prompts",
+	"tab_weekly":       "Weekly",
+	"tab_sessions":     "Sessions",
+	"tab_projects":     "Projects",
+	"tab_cache":        "Cache",
 
 	// Live view
 	"active_session_block": "Active Session Block",
@@ -25,6 +31,7 @@ var en = map[string]string{
 	"until_reset":          "until reset",
 	"five_hour":            "5h Session",
 	"seven_day":            "7d Window",
+	"tokens_left":          "≈ %s tokens left",
 	"input_tokens":         "Input",
 	"output_tokens":        "Output",
 	"cached":               "(+%s cached)",
@@ -36,6 +43,12 @@ var en = map[string]string{
 	"no_active_session":    "No active session",
 	"tokens_per_min":       "Tokens/min",
 	"cost_per_hour":        "Cost/hour",
+	"forecast":             "Forecast",
+	"forecast_today":       "Today ($%s so far)",
+	"forecast_month":       "Month end ($%s so far)",
+	"forecast_week":        "7d tokens by %s",
+	"forecast_note":        "weighted recent rate $%s/h · 90%% band",
+	"forecast_month_line":  "Forecast: $%s by month end (90%%: $%s – $%s) at $%s/h",
 	"projected_tokens":     "Projected tokens",
 	"projected_cost":       "Projected cost",
 	"model_breakdown":      "Model Usage",
@@ -58,12 +71,16 @@ var en = map[string]string{
 	"block_detail":     "Block Detail: %s - %s",
 	"recent_entries":   "Recent Entries",
 	"detail_back_help": "esc/backspace: back to list",
+	"idle_gap":         "idle %s",
+	"block_anchored":   "Window observed from the usage API",
+	"block_cut_short":  "Cut short at %s by the next window",
+	"anomaly_note":     "▲ Unusual: typically $%.2f, %.1f spreads above",
 
 	// Daily Report view (calendar)
 	"total":           "Total",
 	"cache_create":    "Cache W",
 	"cache_read":      "Cache R",
-	"change_month_help": "left/right: change month",
+	"change_month_help": "h(←)/l(→): change month  v: next view",
 	"day_mon":           "Mon",
 	"day_tue":           "Tue",
 	"day_wed":           "Wed",
@@ -71,10 +88,103 @@ var en = map[string]string{
 	"day_fri":           "Fri",
 	"day_sat":           "Sat",
 	"day_sun":           "Sun",
+	"usage_heatmap":     "Usage by Weekday and Hour",
+	"heatmap_help":      "v: next view",
+	"busiest_hours":     "Busiest Hours",
+	"plan_value":        "Plan Value",
+	"plan_not_set":      "Set name or price under [plan] in the config to compare usage with the plan fee",
+	"api_cost":          "API Cost",
+	"plan_fee":          "Fee",
+	"savings":           "Savings",
+	"value_multiple":    "Multiple",
+	"break_even":        "Break-even",
+	"cycle_day":         "day %d",
+	"billing_cycle":     "Billing Cycle",
+	"value_total":       "All cycles: $%s API-equivalent for $%s in fees (%.1f×)",
+	"value_by_project":  "Share of Value by Project",
+
+	// Tools view
+	"tool_usage":       "Tool Usage",
+	"tools_help":       "v: scope (all / today / latest session)",
+	"all_entries":      "All",
+	"latest_session":   "Latest Session",
+	"tool":             "Tool",
+	"turns":            "Turns",
+	"tool_calls":       "Tool Calls",
+	"tool_turns":       "Turns w/ Tools",
+	"distinct_tools":   "Tools Used",
+	"turns_with_tools": "Tool Turn Share",
+	"no_tool_calls":    "No tool calls",
+	"more_tools":       "+%d more",
+
+	// Prompts view
+	"top_prompts":           "Most Expensive Prompts by Session",
+	"no_prompts_found":      "No prompts found",
+	"duration":              "Time",
+	"prompt":                "Prompt",
+	"session_prompts_total": "%d prompts · $%.2f",
+	"more_sessions":         "+%d older sessions",
+
+	// Weekly view
+	"weekly_windows":    "Weekly Windows",
+	"no_weekly_windows": "No weekly windows found",
+	"week":              "Week",
+	"peak":              "Peak",
+	"peak_utilization":  "Peak 7d Utilization",
+	"model_mix":         "Model Mix",
+
+	// Sessions view
+	"sessions":          "Sessions",
+	"no_sessions_found": "No sessions found",
+	"sessions_help":     "j/k: navigate  o: sort  O: reverse  enter: entries",
+	"project":           "Project",
+	"last_activity":     "Last",
+	"models":            "Models",
+	"session_detail":    "Session: %s · %s",
+	"session_entries":   "Entries",
+	"time":              "Time",
+	"more_entries":      "+%d earlier entries",
+
+	// Projects view
+	"projects":          "Projects",
+	"no_projects_found": "No projects found",
+	"projects_help":     "j/k: navigate  o: sort  O: reverse  enter: filter to project",
+	"share":             "Share",
+	"active_days":       "Days",
+
+	// Cache view
+	"cache_efficiency":     "Cache Efficiency",
+	"cache_help":           "v: group (sessions / blocks / projects / days)  j/k: navigate",
+	"cache_days":           "Days",
+	"cache_hit_rate":       "Hit Rate",
+	"cache_read_ratio":     "Read/Write",
+	"cache_net_savings":    "Net Savings",
+	"cache_wasted":         "Wasted Writes",
+	"cache_read_tokens":    "%s read",
+	"cache_written_tokens": "%s written",
+	"cache_premium_paid":   "after $%s premium",
+	"cache_wasted_tokens":  "%s never read",
+	"cache_ratio":          "R/W",
+	"cache_hit":            "Hit",
+	"cache_net":            "Net",
+	"cache_wasted_share":   "Wasted",
+	"cache_wasted_cost":    "Waste $",
+
+	// Anomalies
+	"anomaly_day":     "day",
+	"anomaly_block":   "block",
+	"anomaly_session": "session",
+	"anomaly_notice":  "▲ Unusual %s: $%.2f (typically $%.2f)",
+	"anomaly_more":    "+%d more",
+
+	// Budgets
+	"budgets":         "Budgets",
+	"budget_notice":   "Budget %s: %.0f%% used",
+	"budget_exceeded": "Budget %s exceeded: %.0f%% used",
 
 	// Help overlay
 	"keyboard_shortcuts":    "Keyboard Shortcuts",
-	"help_switch_views":     "Switch to Live / Blocks / Report",
+	"help_switch_views":     "Switch to Live / Blocks / Report / Tools / Prompts / Weekly / Sessions / Projects / Cache",
 	"help_cycle_views":      "Cycle views forward / backward",
 	"help_navigate":         "Navigate list items",
 	"help_drill_down":       "Drill down / select",
@@ -83,7 +193,11 @@ var en = map[string]string{
 	"help_open_settings":    "Open settings",
 	"help_force_refresh":    "Force data refresh",
 	"help_project_filter":   "Project filter",
+	"help_source_filter":    "Cycle data source filter",
+	"help_filter":           "Filter expression (e.g. model~opus and cost>0.5)",
 	"help_navigate_months":  "Navigate months (Report)",
+	"help_tool_scope":       "Cycle scope (Tools) / calendar, heatmap and plan value (Report) / grouping (Cache)",
+	"help_sort":             "Cycle sort column / reverse (Sessions, Projects)",
 	"help_page_scroll":      "Page scroll",
 	"help_top_bottom":       "Jump to top / bottom",
 	"help_mouse_scroll":     "Scroll content",
@@ -101,6 +215,11 @@ var en = map[string]string{
 	"status_help":     "Help",
 	"status_settings": "Settings",
 	"status_project":  "Project",
+	"status_filter":   "Filter",
 	"status_refresh":  "Refresh",
 	"status_quit":     "Quit",
+
+	// Filter prompt
+	"filter":             "filter",
+	"filter_prompt_help": "enter: apply (empty clears)  esc: cancel  ctrl+u: clear",
 }
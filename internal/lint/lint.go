@@ -0,0 +1,139 @@
+// Package lint checks Claude Code logs for signs that the log format has
+// drifted from what the parser understands, so usage totals do not
+// silently shrink after a schema change.
+package lint
+
+import (
+	"context"
+	"strings"
+
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/parser"
+	"github.com/anomredux/claude-smi/internal/pricing"
+)
+
+// Options controls a check.
+type Options struct {
+	Workers int                  // parallel file parsers; <= 0 means one per CPU
+	Prices  pricing.PricingTable // models missing here are reported as unpriced
+}
+
+// FileReport summarizes the health of one log file, or of all files when
+// used as a report total.
+type FileReport struct {
+	Path           string         `json:"path,omitempty"`
+	Source         string         `json:"source,omitempty"`
+	Lines          int            `json:"lines"`
+	Entries        int            `json:"entries"`
+	Malformed      int            `json:"malformed"`
+	BadTimestamps  int            `json:"bad_timestamps"`
+	MissingUsage   int            `json:"missing_usage"`
+	UnknownTypes   map[string]int `json:"unknown_types,omitempty"`
+	Duplicates     int            `json:"duplicates"`
+	UnpricedModels map[string]int `json:"unpriced_models,omitempty"`
+
+	ErrorRate      float64 `json:"error_rate"`      // unusable lines: malformed or bad timestamp
+	DuplicateRatio float64 `json:"duplicate_ratio"` // entries whose key was already seen
+}
+
+// rates fills in ErrorRate and DuplicateRatio from the counts.
+func (r *FileReport) rates() {
+	r.ErrorRate, r.DuplicateRatio = 0, 0
+	if r.Lines > 0 {
+		r.ErrorRate = float64(r.Malformed+r.BadTimestamps) / float64(r.Lines)
+	}
+	if r.Entries > 0 {
+		r.DuplicateRatio = float64(r.Duplicates) / float64(r.Entries)
+	}
+}
+
+// Problems reports whether anything suspicious was found.
+func (r FileReport) Problems() bool {
+	return r.Malformed+r.BadTimestamps+r.MissingUsage > 0 ||
+		len(r.UnknownTypes) > 0 || len(r.UnpricedModels) > 0
+}
+
+func (r *FileReport) add(o FileReport) {
+	r.Lines += o.Lines
+	r.Entries += o.Entries
+	r.Malformed += o.Malformed
+	r.BadTimestamps += o.BadTimestamps
+	r.MissingUsage += o.MissingUsage
+	r.Duplicates += o.Duplicates
+	r.UnknownTypes = addCounts(r.UnknownTypes, o.UnknownTypes)
+	r.UnpricedModels = addCounts(r.UnpricedModels, o.UnpricedModels)
+}
+
+// Report is the result of a check: one report per file and their total.
+type Report struct {
+	Files []FileReport `json:"files"`
+	Total FileReport   `json:"total"`
+}
+
+// Check parses every log under the data directories from scratch and
+// reports per-file and total health. Duplicates are counted across all
+// files, in the order the files are listed.
+func Check(ctx context.Context, dataDirs []string, opts Options) Report {
+	var report Report
+	seen := make(domain.KeySet)
+	unpriced := make(map[string]bool) // memoized price lookups
+
+	scanOpts := parser.ScanOptions{Workers: opts.Workers}
+	parser.ScanEach(ctx, dataDirs, scanOpts, func(f parser.FileResult) {
+		fr := FileReport{
+			Path:          f.Path,
+			Source:        f.Source,
+			Lines:         f.Stats.Lines,
+			Entries:       len(f.Entries),
+			Malformed:     f.Stats.Malformed,
+			BadTimestamps: f.Stats.BadTimestamps,
+			MissingUsage:  f.Stats.MissingUsage,
+		}
+		for typ, n := range f.Stats.Types {
+			if !parser.KnownRecordTypes[typ] {
+				fr.UnknownTypes = addCounts(fr.UnknownTypes, map[string]int{typ: n})
+			}
+		}
+		for _, e := range f.Entries {
+			if !seen.Add(e) {
+				fr.Duplicates++
+			}
+			if !priced(e.Model, opts.Prices, unpriced) {
+				fr.UnpricedModels = addCounts(fr.UnpricedModels, map[string]int{e.Model: 1})
+			}
+		}
+		fr.rates()
+		report.Files = append(report.Files, fr)
+		report.Total.add(fr)
+	})
+	report.Total.rates()
+	return report
+}
+
+// priced reports whether a model has a price. Placeholder models such as
+// "<synthetic>" never cost anything and count as priced.
+func priced(model string, prices pricing.PricingTable, unpriced map[string]bool) bool {
+	if model == "" || strings.HasPrefix(model, "<") {
+		return true
+	}
+	missing, ok := unpriced[model]
+	if !ok {
+		_, found := prices.Lookup(model)
+		missing = !found
+		unpriced[model] = missing
+	}
+	return !missing
+}
+
+func addCounts(dst, src map[string]int) map[string]int {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]int, len(src))
+	}
+	for k, n := range src {
+		dst[k] += n
+	}
+	return dst
+}
@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anomredux/claude-smi/internal/pricing"
+)
+
+const (
+	lineOpus    = `{"type":"assistant","timestamp":"2026-02-19T10:00:00.000Z","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":100}}}`
+	lineMystery = `{"type":"assistant","timestamp":"2026-02-19T11:00:00.000Z","requestId":"r2","message":{"id":"m2","model":"mystery-1","usage":{"input_tokens":100}}}`
+)
+
+func writeLog(t *testing.T, path string, lines ...string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, filepath.Join(dir, "a", "1.jsonl"),
+		lineOpus,
+		`{"type":"telemetry","timestamp":"2026-02-19T10:00:00.000Z"}`,
+		`{broken`,
+	)
+	writeLog(t, filepath.Join(dir, "b", "2.jsonl"), lineOpus, lineMystery)
+
+	prices := pricing.PricingTable{"claude-opus-4-6": {Input: 5}}
+	report := Check(context.Background(), []string{dir}, Options{Prices: prices})
+
+	if len(report.Files) != 2 {
+		t.Fatalf("got %d file reports, want 2", len(report.Files))
+	}
+	a, b, total := report.Files[0], report.Files[1], report.Total
+	if a.Malformed != 1 || a.UnknownTypes["telemetry"] != 1 {
+		t.Errorf("file a = %+v, want 1 malformed line and 1 telemetry record", a)
+	}
+	if b.Duplicates != 1 || b.DuplicateRatio != 0.5 {
+		t.Errorf("file b duplicates = %d (%.2f), want 1 (0.50)", b.Duplicates, b.DuplicateRatio)
+	}
+	if total.UnpricedModels["mystery-1"] != 1 || len(total.UnpricedModels) != 1 {
+		t.Errorf("UnpricedModels = %v, want only mystery-1", total.UnpricedModels)
+	}
+	if total.Lines != 5 || total.ErrorRate != 0.2 {
+		t.Errorf("total lines = %d, ErrorRate = %.2f, want 5 and 0.20", total.Lines, total.ErrorRate)
+	}
+	if !b.Problems() || (FileReport{}).Problems() {
+		t.Error("Problems() should flag the unpriced model and nothing in an empty report")
+	}
+}
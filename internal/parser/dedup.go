@@ -7,29 +7,52 @@ import (
 )
 
 // Dedup removes duplicate entries based on MessageID:RequestID.
-// Keeps the first occurrence (earliest timestamp).
+// Keeps the first occurrence (earliest timestamp), which takes over the
+// tool calls of the dropped ones.
 // Note: sorts the input slice in place.
 func Dedup(entries []domain.UsageEntry) []domain.UsageEntry {
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].Timestamp.Before(entries[j].Timestamp)
 	})
+	foldTools(entries)
 
-	seen := make(map[string]struct{}, len(entries))
+	// Entries without MessageID and RequestID can't be deduped and are kept.
+	seen := make(domain.KeySet, len(entries))
 	result := make([]domain.UsageEntry, 0, len(entries))
 
 	for _, e := range entries {
-		key := e.DedupKey()
-		if key == ":" {
-			// Both MessageID and RequestID empty -- keep as-is (can't dedup)
+		if seen.Add(e) {
 			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// foldTools moves the tool calls of repeated records onto the first record
+// with the same dedup key. Claude Code writes one record per content block
+// of a message, all carrying the message's id and usage, so only together
+// do they list every tool the message called. Moving rather than copying
+// keeps folding idempotent, and a record with the same timestamp as the
+// first is a second copy of it (e.g. a re-read file), not another block.
+func foldTools(entries []domain.UsageEntry) {
+	first := make(map[uint64]int)
+	for i := range entries {
+		e := &entries[i]
+		if !e.HasDedupKey() {
 			continue
 		}
-		if _, exists := seen[key]; exists {
+		h := e.KeyHash()
+		j, ok := first[h]
+		if !ok {
+			first[h] = i
 			continue
 		}
-		seen[key] = struct{}{}
-		result = append(result, e)
+		if len(e.Tools) > 0 && !e.Timestamp.Equal(entries[j].Timestamp) {
+			// Never append into a slice shared with a cached entry.
+			tools := entries[j].Tools
+			entries[j].Tools = append(tools[:len(tools):len(tools)], e.Tools...)
+			e.Tools = nil
+		}
 	}
-
-	return result
 }
@@ -39,3 +39,25 @@ func TestDedup_Empty(t *testing.T) {
 		t.Errorf("got %d entries, want 0", len(result))
 	}
 }
+
+func TestDedup_FoldsTools(t *testing.T) {
+	now := time.Now()
+	entries := []domain.UsageEntry{
+		{Timestamp: now.Add(time.Second), MessageID: "m1", RequestID: "r1", Tools: []string{"Edit"}},
+		{Timestamp: now, MessageID: "m1", RequestID: "r1", Tools: []string{"Read"}},
+		{Timestamp: now.Add(2 * time.Second), MessageID: "m2", RequestID: "r2"},
+	}
+
+	result := Dedup(entries)
+	if len(result) != 2 {
+		t.Fatalf("got %d entries, want 2", len(result))
+	}
+	if len(result[0].Tools) != 2 || result[0].Tools[0] != "Read" || result[0].Tools[1] != "Edit" {
+		t.Errorf("Tools = %v, want [Read Edit]", result[0].Tools)
+	}
+
+	// Deduplicating again must not repeat the folded calls.
+	if again := Dedup(append(result, entries...)); len(again[0].Tools) != 2 {
+		t.Errorf("second pass Tools = %v, want [Read Edit]", again[0].Tools)
+	}
+}
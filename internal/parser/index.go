@@ -0,0 +1,483 @@
+package parser
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// indexVersion is bumped whenever parsing changes in a way that makes
+// previously cached entries stale. A mismatched index is discarded on load.
+const indexVersion = 9
+
+// FileState records how much of a single JSONL file has been parsed and
+// the accumulated parse result so far.
+type FileState struct {
+	Size    int64
+	ModTime time.Time
+	Inode   uint64
+	Offset  int64  // bytes consumed; parsing resumes here
+	Source  string // name of the data directory the file belongs to
+	ParseResult
+}
+
+// Index is a persistent cache of parsed entries keyed by file path.
+// It lets a scan skip unchanged files and parse only appended bytes.
+//
+// On disk an index is a gob stream of an indexHeader followed by one
+// fileRecord per file in path order, so it can be read and written one
+// file at a time (see ScanIndexed).
+type Index struct {
+	Version int
+	Files   map[string]*FileState
+}
+
+// indexHeader starts an index file.
+type indexHeader struct {
+	Version int
+}
+
+// fileRecord is the state of one file in an index file.
+type fileRecord struct {
+	Path string
+	FileState
+}
+
+// NewIndex returns an empty index.
+func NewIndex() *Index {
+	return &Index{Version: indexVersion, Files: make(map[string]*FileState)}
+}
+
+// LoadIndex reads an index from disk. A missing, corrupt or outdated index
+// yields an empty one, so callers never need to handle errors.
+func LoadIndex(path string) *Index {
+	r, err := openIndex(path)
+	if err != nil {
+		return NewIndex()
+	}
+	defer r.Close()
+
+	idx := NewIndex()
+	for ; r.next != nil; r.advance() {
+		st := r.next.FileState
+		idx.Files[r.next.Path] = &st
+	}
+	if r.err != nil {
+		return NewIndex()
+	}
+	return idx
+}
+
+// Save writes the index to disk atomically (temp file + rename).
+func (idx *Index) Save(path string) error {
+	w, err := createIndex(path)
+	if err != nil {
+		return err
+	}
+	defer w.Abort()
+
+	paths := make([]string, 0, len(idx.Files))
+	for p := range idx.Files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		w.Put(p, idx.Files[p])
+	}
+	return w.Commit()
+}
+
+// Scan walks the data directories, parses new or changed .jsonl files
+// (only the appended bytes when possible) with a bounded worker pool and
+// returns the accumulated result of every file in path order. Entries are
+// tagged with the SourceName of their directory. Files that disappeared
+// are dropped from the index.
+func (idx *Index) Scan(ctx context.Context, dataDirs []string, opts ScanOptions) ScanResult {
+	files := collectFiles(ctx, dataDirs)
+	type job struct {
+		file  indexedFile
+		state *FileState // start state; parsing resumes at its Offset
+	}
+	var jobs []job
+	for _, f := range files {
+		if st, stale := startState(idx.Files[f.path], f.info); stale {
+			jobs = append(jobs, job{file: f, state: st})
+		}
+	}
+
+	parsed := make([]*FileState, len(jobs))
+	parallel(ctx, len(jobs), opts.workers(len(jobs)), func(i int) {
+		parsed[i], _ = resume(jobs[i].file, jobs[i].state)
+	})
+	for i, st := range parsed {
+		if st != nil {
+			idx.Files[jobs[i].file.path] = st
+		}
+	}
+
+	// A cancelled walk has not visited every file, so keep the rest.
+	if ctx.Err() == nil {
+		seen := make(map[string]struct{}, len(files))
+		for _, f := range files {
+			seen[f.path] = struct{}{}
+		}
+		for path := range idx.Files {
+			if _, ok := seen[path]; !ok {
+				delete(idx.Files, path)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(idx.Files))
+	for path := range idx.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	sr := ScanResult{Files: make([]FileResult, 0, len(paths))}
+	for _, path := range paths {
+		st := idx.Files[path]
+		sr.Files = append(sr.Files, FileResult{Path: path, Source: st.Source, ParseResult: st.ParseResult})
+	}
+	return sr
+}
+
+// ScanIndexed is Scan over the index file at indexPath, passing each
+// file's result to fn in path order instead of returning them. The old
+// index is read and the updated one written a file at a time, and only a
+// few batches of files are in memory at once, so memory does not grow
+// with the history. The updated index replaces the old one unless ctx is
+// cancelled; an error writing it is returned after every file was passed.
+func ScanIndexed(ctx context.Context, indexPath string, dataDirs []string, opts ScanOptions, fn func(FileResult)) error {
+	files := collectFiles(ctx, dataDirs)
+	old, _ := openIndex(indexPath) // nil: parse every file
+	defer old.Close()
+	w, err := createIndex(indexPath)
+	defer w.Abort()
+
+	workers := opts.workers(len(files))
+	batch := 4 * workers
+	for start := 0; start < len(files); start += batch {
+		chunk := files[start:min(start+batch, len(files))]
+		cached := make([]*FileState, len(chunk))
+		for i, f := range chunk {
+			cached[i] = old.find(f.path)
+		}
+		states := make([]*FileState, len(chunk))
+		parallel(ctx, len(chunk), workers, func(i int) {
+			st, stale := startState(cached[i], chunk[i].info)
+			if stale {
+				var ok bool
+				if st, ok = resume(chunk[i], st); !ok {
+					st = cached[i] // unreadable: keep what is known
+				}
+			}
+			states[i] = st
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		for i, st := range states {
+			if st == nil {
+				continue
+			}
+			w.Put(chunk[i].path, st)
+			fn(FileResult{Path: chunk[i].path, Source: st.Source, ParseResult: st.ParseResult})
+		}
+	}
+	if err != nil {
+		return err
+	}
+	old.Close() // before replacing the file it reads
+	return w.Commit()
+}
+
+// EachIndexed passes the result of every file in the index file at path to
+// fn, in path order and one file at a time.
+func EachIndexed(path string, fn func(FileResult)) error {
+	r, err := openIndex(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	for ; r.next != nil; r.advance() {
+		fn(FileResult{Path: r.next.Path, Source: r.next.Source, ParseResult: r.next.ParseResult})
+	}
+	return r.err
+}
+
+// Positions returns the parsed offset and inode of every indexed file,
+// ready to seed incremental parsing.
+func (idx *Index) Positions() map[string]FileChange {
+	positions := make(map[string]FileChange, len(idx.Files))
+	for path, st := range idx.Files {
+		positions[path] = FileChange{Path: path, Offset: st.Offset, Inode: st.Inode, Source: st.Source}
+	}
+	return positions
+}
+
+// indexedFile is a .jsonl file found by collectFiles.
+type indexedFile struct {
+	path   string
+	source string
+	info   fs.FileInfo
+}
+
+// collectFiles returns the .jsonl files under the data directories in
+// path order. A file under several (nested) directories is listed once,
+// with the source of the first.
+func collectFiles(ctx context.Context, dataDirs []string) []indexedFile {
+	seen := make(map[string]struct{})
+	var files []indexedFile
+	for _, dataDir := range dataDirs {
+		source := SourceName(dataDir)
+		_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+				return nil
+			}
+			if _, dup := seen[path]; dup {
+				return nil // nested data directories
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			seen[path] = struct{}{}
+			files = append(files, indexedFile{path: path, source: source, info: info})
+			return nil
+		})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files
+}
+
+// startState returns the state a file should be parsed from, given its
+// cached state (nil if none), and whether it needs parsing at all.
+func startState(st *FileState, info fs.FileInfo) (*FileState, bool) {
+	inode := FileInode(info)
+	if st != nil && st.Inode == inode && st.Size == info.Size() && st.ModTime.Equal(info.ModTime()) {
+		return st, false // unchanged
+	}
+	// Shrunk or replaced: the cached entries no longer describe the file.
+	if st == nil || st.Inode != inode || info.Size() < st.Offset {
+		return &FileState{Inode: inode}, true
+	}
+	return st, true
+}
+
+// resume parses a file from the offset of its start state and returns the
+// combined state, or false if the file could not be read. The start state
+// is never modified, so it may be shared with a cached index.
+func resume(f indexedFile, start *FileState) (*FileState, bool) {
+	res, offset, ok := parseFrom(f.path, start.Offset)
+	if !ok {
+		return nil, false
+	}
+	for k := range res.Entries {
+		res.Entries[k].Source = f.source
+	}
+	// Copy the state and force append to reallocate.
+	st := *start
+	st.Entries = append(st.Entries[:len(st.Entries):len(st.Entries)], res.Entries...)
+	if len(start.Entries) > 0 {
+		foldTools(st.Entries) // a message may span both parses
+	}
+	st.Prompts = append(st.Prompts[:len(st.Prompts):len(st.Prompts)], res.Prompts...)
+	st.SkipCount += res.SkipCount
+	st.ErrorCount += res.ErrorCount
+	st.Stats = st.Stats.Add(res.Stats)
+	st.Offset = offset
+	st.Source = f.source
+	st.Size = f.info.Size()
+	st.ModTime = f.info.ModTime()
+	return &st, true
+}
+
+// parseFrom parses a file starting at offset and returns the result along
+// with the offset after the last complete line.
+func parseFrom(path string, offset int64) (ParseResult, int64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ParseResult{}, 0, false
+	}
+	defer f.Close()
+
+	if offset > 0 {
+		if _, err := f.Seek(offset, io.SeekStart); err != nil {
+			return ParseResult{}, 0, false
+		}
+	}
+
+	result, n := parseStream(f, filepath.Dir(path))
+	return result, offset + n, true
+}
+
+// indexReader reads the records of an index file one at a time.
+type indexReader struct {
+	f    *os.File
+	dec  *gob.Decoder
+	next *fileRecord // nil once every record was read
+	err  error       // decoding error that ended the records early
+}
+
+// openIndex opens an index file and reads its first record.
+func openIndex(path string) (*indexReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &indexReader{f: f, dec: gob.NewDecoder(bufio.NewReader(f))}
+	var h indexHeader
+	if err := r.dec.Decode(&h); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("decode index: %w", err)
+	}
+	if h.Version != indexVersion {
+		f.Close()
+		return nil, fmt.Errorf("index version %d, want %d", h.Version, indexVersion)
+	}
+	r.advance()
+	return r, nil
+}
+
+// advance reads the next record.
+func (r *indexReader) advance() {
+	var rec fileRecord
+	if err := r.dec.Decode(&rec); err != nil {
+		if err != io.EOF {
+			r.err = fmt.Errorf("decode index: %w", err)
+		}
+		r.next = nil
+		return
+	}
+	r.next = &rec
+}
+
+// find returns the state of path, skipping the records before it. Paths
+// must be asked for in increasing order. A nil reader finds nothing.
+func (r *indexReader) find(path string) *FileState {
+	if r == nil {
+		return nil
+	}
+	for r.next != nil && r.next.Path < path {
+		r.advance()
+	}
+	if r.next == nil || r.next.Path != path {
+		return nil
+	}
+	st := r.next.FileState
+	r.advance()
+	return &st
+}
+
+// Close closes the file. A nil or closed reader is ignored.
+func (r *indexReader) Close() {
+	if r != nil && r.f != nil {
+		r.f.Close()
+		r.f = nil
+	}
+}
+
+// indexWriter writes an index file one record at a time to a temp file
+// that Commit moves into place.
+type indexWriter struct {
+	path string
+	tmp  *os.File
+	buf  *bufio.Writer
+	enc  *gob.Encoder
+	err  error // first write error; later writes are skipped
+	done bool
+}
+
+// createIndex starts writing the index at path. The returned writer is
+// never nil, so Abort can always be deferred.
+func createIndex(path string) (*indexWriter, error) {
+	w := &indexWriter{path: path, done: true}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return w, fmt.Errorf("create cache dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".index-*")
+	if err != nil {
+		return w, fmt.Errorf("create temp index: %w", err)
+	}
+	w.tmp, w.done = tmp, false
+	w.buf = bufio.NewWriter(tmp)
+	w.enc = gob.NewEncoder(w.buf)
+	if err := w.enc.Encode(indexHeader{Version: indexVersion}); err != nil {
+		w.err = fmt.Errorf("encode index: %w", err)
+	}
+	return w, nil
+}
+
+// Put appends the state of one file. Files must be put in path order.
+func (w *indexWriter) Put(path string, st *FileState) {
+	if w.done || w.err != nil {
+		return
+	}
+	if err := w.enc.Encode(fileRecord{Path: path, FileState: *st}); err != nil {
+		w.err = fmt.Errorf("encode index: %w", err)
+	}
+}
+
+// Commit finishes the file and moves it into place.
+func (w *indexWriter) Commit() error {
+	if w.done {
+		return nil
+	}
+	w.done = true
+	defer os.Remove(w.tmp.Name())
+	if w.err == nil {
+		if err := w.buf.Flush(); err != nil {
+			w.err = fmt.Errorf("write index: %w", err)
+		}
+	}
+	if err := w.tmp.Close(); err != nil && w.err == nil {
+		w.err = fmt.Errorf("close temp index: %w", err)
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if err := os.Rename(w.tmp.Name(), w.path); err != nil {
+		return fmt.Errorf("replace index: %w", err)
+	}
+	return nil
+}
+
+// Abort discards an uncommitted file.
+func (w *indexWriter) Abort() {
+	if w.done {
+		return
+	}
+	w.done = true
+	w.tmp.Close()
+	os.Remove(w.tmp.Name())
+}
+
+// SourceName returns the name entries from a data directory are tagged
+// with: the Claude config dir owning it, with the home dir shortened to
+// "~" (e.g. "~/.claude" for ~/.claude/projects).
+func SourceName(dataDir string) string {
+	dir := filepath.Clean(dataDir)
+	if filepath.Base(dir) == "projects" {
+		dir = filepath.Dir(dir)
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if rel, err := filepath.Rel(home, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			if rel == "." {
+				return "~"
+			}
+			return "~" + string(filepath.Separator) + rel
+		}
+	}
+	return dir
+}
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	indexLine1 = `{"type":"assistant","timestamp":"2026-02-19T10:00:00.000Z","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"claude-opus-4-6","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`
+	indexLine2 = `{"type":"assistant","timestamp":"2026-02-19T11:00:00.000Z","sessionId":"s1","requestId":"r2","message":{"id":"m2","model":"claude-opus-4-6","usage":{"input_tokens":200,"output_tokens":80,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`
+)
+
+func writeLog(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func appendLog(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndex_ScanMatchesScanAndParse(t *testing.T) {
+	dir := testdataDir(t)
+	ctx := context.Background()
+
+	entries := NewIndex().Scan(ctx, []string{dir}, ScanOptions{}).Entries()
+	if want := len(ScanAndParse(ctx, dir)); len(entries) != want {
+		t.Fatalf("got %d entries, want %d", len(entries), want)
+	}
+}
+
+func TestIndex_SaveAndLoad(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n")
+
+	ctx := context.Background()
+	idx := NewIndex()
+	idx.Scan(ctx, []string{dataDir}, ScanOptions{})
+
+	indexPath := filepath.Join(t.TempDir(), "cache", "index.gob")
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded := LoadIndex(indexPath)
+	st, ok := loaded.Files[logPath]
+	if !ok {
+		t.Fatal("loaded index is missing the log file")
+	}
+	if len(st.Entries) != 1 || st.Entries[0].MessageID != "m1" {
+		t.Errorf("loaded entries = %+v, want one entry m1", st.Entries)
+	}
+	if st.Offset != int64(len(indexLine1)+1) {
+		t.Errorf("Offset = %d, want %d", st.Offset, len(indexLine1)+1)
+	}
+}
+
+func TestIndex_ParsesOnlyAppendedBytes(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n")
+
+	ctx := context.Background()
+	idx := NewIndex()
+	idx.Scan(ctx, []string{dataDir}, ScanOptions{})
+
+	// Tamper with the cached entry: if the file were re-parsed from zero
+	// the marker would be lost.
+	idx.Files[logPath].Entries[0].Model = "cached"
+
+	appendLog(t, logPath, indexLine2+"\n")
+	entries := idx.Scan(ctx, []string{dataDir}, ScanOptions{}).Entries()
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Model != "cached" {
+		t.Errorf("first entry was re-parsed; Model = %q, want %q", entries[0].Model, "cached")
+	}
+	if entries[1].MessageID != "m2" {
+		t.Errorf("second entry MessageID = %q, want m2", entries[1].MessageID)
+	}
+}
+
+func TestIndex_ShrunkFileIsReparsed(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n"+indexLine2+"\n")
+
+	ctx := context.Background()
+	idx := NewIndex()
+	if got := len(idx.Scan(ctx, []string{dataDir}, ScanOptions{}).Entries()); got != 2 {
+		t.Fatalf("initial scan got %d entries, want 2", got)
+	}
+
+	writeLog(t, logPath, indexLine2+"\n")
+	entries := idx.Scan(ctx, []string{dataDir}, ScanOptions{}).Entries()
+	if len(entries) != 1 || entries[0].MessageID != "m2" {
+		t.Errorf("after shrink got %+v, want only m2", entries)
+	}
+}
+
+func TestIndex_DropsDeletedFiles(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n")
+
+	ctx := context.Background()
+	idx := NewIndex()
+	idx.Scan(ctx, []string{dataDir}, ScanOptions{})
+
+	if err := os.Remove(logPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(idx.Scan(ctx, []string{dataDir}, ScanOptions{}).Entries()); got != 0 {
+		t.Errorf("got %d entries after delete, want 0", got)
+	}
+	if len(idx.Files) != 0 {
+		t.Errorf("index still tracks %d files", len(idx.Files))
+	}
+}
+
+func TestLoadIndex_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.gob")
+	if err := os.WriteFile(path, []byte("not gob"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	idx := LoadIndex(path)
+	if idx == nil || len(idx.Files) != 0 {
+		t.Errorf("corrupt index should load as empty, got %+v", idx)
+	}
+
+	idx = LoadIndex("/nonexistent/index.gob")
+	if idx == nil || len(idx.Files) != 0 {
+		t.Errorf("missing index should load as empty, got %+v", idx)
+	}
+}
+
+func TestIndex_PartialLineIsRereadWhenComplete(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n"+indexLine2[:30])
+
+	ctx := context.Background()
+	idx := NewIndex()
+	if got := len(idx.Scan(ctx, []string{dataDir}, ScanOptions{}).Entries()); got != 1 {
+		t.Fatalf("got %d entries, want 1", got)
+	}
+
+	appendLog(t, logPath, indexLine2[30:]+"\n")
+	result := idx.Scan(ctx, []string{dataDir}, ScanOptions{})
+	if got := len(result.Entries()); got != 2 {
+		t.Errorf("got %d entries after completing the line, want 2", got)
+	}
+	if _, errs := result.Totals(); errs != 0 {
+		t.Errorf("ErrorCount = %d, want 0", errs)
+	}
+}
+
+func TestIndex_ScanTagsSources(t *testing.T) {
+	base := t.TempDir()
+	work := filepath.Join(base, "work", "projects")
+	home := filepath.Join(base, "home", "projects")
+	writeLog(t, filepath.Join(work, "proj", "a.jsonl"), indexLine1+"\n")
+	writeLog(t, filepath.Join(home, "proj", "b.jsonl"), indexLine2+"\n")
+
+	idx := NewIndex()
+	entries := idx.Scan(context.Background(), []string{work, home}, ScanOptions{}).Entries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	want := map[string]string{"m1": SourceName(work), "m2": SourceName(home)}
+	for _, e := range entries {
+		if e.Source != want[e.MessageID] {
+			t.Errorf("%s Source = %q, want %q", e.MessageID, e.Source, want[e.MessageID])
+		}
+	}
+	if got := idx.Positions()[filepath.Join(home, "proj", "b.jsonl")].Source; got != SourceName(home) {
+		t.Errorf("position Source = %q, want %q", got, SourceName(home))
+	}
+}
+
+func TestScanIndexed(t *testing.T) {
+	dataDir := t.TempDir()
+	logA := filepath.Join(dataDir, "a", "log.jsonl")
+	logB := filepath.Join(dataDir, "b", "log.jsonl")
+	writeLog(t, logA, indexLine1+"\n")
+	writeLog(t, logB, indexLine2+"\n")
+	indexPath := filepath.Join(t.TempDir(), "cache", "index.gob")
+	ctx := context.Background()
+
+	scan := func() []FileResult {
+		t.Helper()
+		var files []FileResult
+		if err := ScanIndexed(ctx, indexPath, []string{dataDir}, ScanOptions{}, func(f FileResult) {
+			files = append(files, f)
+		}); err != nil {
+			t.Fatalf("ScanIndexed failed: %v", err)
+		}
+		return files
+	}
+
+	files := scan()
+	if len(files) != 2 || files[0].Path != logA || files[1].Path != logB {
+		t.Fatalf("got files %+v, want %s and %s in path order", files, logA, logB)
+	}
+	if st := LoadIndex(indexPath).Files[logA]; st == nil || len(st.Entries) != 1 {
+		t.Fatalf("index was not written: %+v", st)
+	}
+
+	// Tamper with the cached entry: re-parsing from zero would lose it.
+	idx := LoadIndex(indexPath)
+	idx.Files[logA].Entries[0].Model = "cached"
+	if err := idx.Save(indexPath); err != nil {
+		t.Fatal(err)
+	}
+	appendLog(t, logA, indexLine2+"\n")
+	if err := os.Remove(logB); err != nil {
+		t.Fatal(err)
+	}
+
+	files = scan()
+	if len(files) != 1 || len(files[0].Entries) != 2 {
+		t.Fatalf("got %+v, want only %s with 2 entries", files, logA)
+	}
+	if files[0].Entries[0].Model != "cached" {
+		t.Errorf("first entry was re-parsed; Model = %q, want %q", files[0].Entries[0].Model, "cached")
+	}
+
+	var stored int
+	if err := EachIndexed(indexPath, func(f FileResult) { stored += len(f.Entries) }); err != nil {
+		t.Fatalf("EachIndexed failed: %v", err)
+	}
+	if stored != 2 {
+		t.Errorf("EachIndexed saw %d entries, want 2", stored)
+	}
+}
+
+func TestSourceName(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := []struct{ dir, want string }{
+		{filepath.Join(home, ".claude", "projects"), filepath.Join("~", ".claude")},
+		{filepath.Join(home, ".config", "claude"), filepath.Join("~", ".config", "claude")},
+		{"/srv/claude/projects", "/srv/claude"},
+	}
+	for _, tt := range tests {
+		if got := SourceName(tt.dir); got != tt.want {
+			t.Errorf("SourceName(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package parser
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// FileInode returns the inode number used to detect replaced files.
+func FileInode(info fs.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Ino)
+	}
+	return 0
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"io"
 	"time"
@@ -11,48 +12,171 @@ import (
 
 // rawRecord maps the JSONL structure we care about.
 type rawRecord struct {
-	Type      string   `json:"type"`
-	Timestamp string   `json:"timestamp"`
-	SessionID string   `json:"sessionId"`
-	RequestID string   `json:"requestId"`
-	CostUSD   *float64 `json:"costUSD"`
-	Message   *struct {
-		ID    string `json:"id"`
-		Model string `json:"model"`
-		Usage *struct {
-			InputTokens              int `json:"input_tokens"`
-			OutputTokens             int `json:"output_tokens"`
-			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
-			CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	Type        string   `json:"type"`
+	UUID        string   `json:"uuid"`
+	ParentUUID  string   `json:"parentUuid"`
+	IsMeta      bool     `json:"isMeta"`
+	Timestamp   string   `json:"timestamp"`
+	SessionID   string   `json:"sessionId"`
+	RequestID   string   `json:"requestId"`
+	CostUSD     *float64 `json:"costUSD"`
+	Cwd         string   `json:"cwd"`
+	GitBranch   string   `json:"gitBranch"`
+	Version     string   `json:"version"`
+	IsSidechain bool     `json:"isSidechain"`
+	UserType    string   `json:"userType"`
+	Message     *struct {
+		ID      string          `json:"id"`
+		Model   string          `json:"model"`
+		Content json.RawMessage `json:"content"` // a string in user records
+		Usage   *struct {
+			InputTokens              int    `json:"input_tokens"`
+			OutputTokens             int    `json:"output_tokens"`
+			CacheCreationInputTokens int    `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int    `json:"cache_read_input_tokens"`
+			ServiceTier              string `json:"service_tier"`
+			ServerToolUse            *struct {
+				WebSearchRequests int `json:"web_search_requests"`
+				WebFetchRequests  int `json:"web_fetch_requests"`
+			} `json:"server_tool_use"`
+			CacheCreation *struct {
+				Ephemeral5mInputTokens int `json:"ephemeral_5m_input_tokens"`
+				Ephemeral1hInputTokens int `json:"ephemeral_1h_input_tokens"`
+			} `json:"cache_creation"`
 		} `json:"usage"`
 	} `json:"message"`
 }
 
-// ParseResult holds parsed entries and error stats.
+// ParseResult holds parsed entries, the user prompts that started them
+// and error stats.
 type ParseResult struct {
 	Entries    []domain.UsageEntry
+	Prompts    []domain.Prompt
 	SkipCount  int
 	ErrorCount int
+	Stats      ParseStats
+}
+
+// ParseStats breaks the skip and error counts down by cause, so changes
+// in the log schema can be spotted.
+type ParseStats struct {
+	Lines         int            // non-empty lines read
+	Malformed     int            // lines that are not valid JSON
+	MissingUsage  int            // assistant records without message.usage
+	BadTimestamps int            // assistant records with an unparseable timestamp
+	Oversized     int            // lines longer than maxLineSize, skipped unparsed
+	Types         map[string]int // records per "type" value
+}
+
+// KnownRecordTypes lists the record types Claude Code is known to write.
+var KnownRecordTypes = map[string]bool{
+	"assistant":             true,
+	"user":                  true,
+	"system":                true,
+	"progress":              true,
+	"summary":               true,
+	"file-history-snapshot": true,
+	"queue-operation":       true,
+}
+
+// Add returns the sum of two stats without modifying either.
+func (s ParseStats) Add(o ParseStats) ParseStats {
+	sum := ParseStats{
+		Lines:         s.Lines + o.Lines,
+		Malformed:     s.Malformed + o.Malformed,
+		MissingUsage:  s.MissingUsage + o.MissingUsage,
+		BadTimestamps: s.BadTimestamps + o.BadTimestamps,
+		Oversized:     s.Oversized + o.Oversized,
+	}
+	if len(s.Types)+len(o.Types) > 0 {
+		sum.Types = make(map[string]int, len(s.Types)+len(o.Types))
+		for t, n := range s.Types {
+			sum.Types[t] += n
+		}
+		for t, n := range o.Types {
+			sum.Types[t] += n
+		}
+	}
+	return sum
 }
 
 // ParseReader reads JSONL from an io.Reader, streaming line by line.
 // projectPath is derived from the file path for project filtering.
+// A trailing line without a newline that is not valid JSON is treated as
+// still being written and is ignored rather than counted as an error.
 func ParseReader(r io.Reader, projectPath string) ParseResult {
+	result, _ := parseStream(r, projectPath)
+	return result
+}
+
+// maxLineSize is the longest line parsed. Longer lines are skipped.
+const maxLineSize = 10 * 1024 * 1024
+
+// parseStream parses JSONL like ParseReader and also returns the number of
+// bytes consumed, which always ends on a line boundary (or after a complete
+// unterminated JSON record). Resuming from that offset re-reads any line
+// that was only partially written.
+func parseStream(r io.Reader, projectPath string) (ParseResult, int64) {
 	var result ParseResult
+	result.Stats.Types = make(map[string]int)
+	chain := newPromptChain()
+	var consumed int64
+	// An oversized line is discarded as it streams by; consumed only moves
+	// past it once its newline has been read.
+	var oversized bool
+	var discarded int64
 	scanner := bufio.NewScanner(r)
-	scanner.Buffer(make([]byte, 0, 1024*1024), 10*1024*1024) // 10MB max line
+	scanner.Buffer(make([]byte, 0, 1024*1024), maxLineSize)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if oversized {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				discarded += int64(len(data))
+				return len(data), nil, nil
+			}
+			consumed += discarded + int64(i+1)
+			oversized, discarded = false, 0
+			result.Stats.Lines++
+			result.Stats.Oversized++
+			result.SkipCount++
+			return i + 1, nil, nil
+		}
+		advance, token := splitCompleteLine(data, atEOF)
+		if advance == 0 && len(data) >= maxLineSize {
+			oversized, discarded = true, int64(len(data))
+			return len(data), nil, nil
+		}
+		consumed += int64(advance)
+		return advance, token, nil
+	})
 
 	for scanner.Scan() {
 		line := scanner.Bytes()
 		if len(line) == 0 {
 			continue
 		}
+		result.Stats.Lines++
 
 		var rec rawRecord
 		if err := json.Unmarshal(line, &rec); err != nil {
 			result.ErrorCount++
+			result.Stats.Malformed++
 			continue
 		}
+		result.Stats.Types[rec.Type]++
+
+		text, isPrompt := promptText(&rec)
+		promptID := chain.link(&rec, isPrompt)
+		if isPrompt {
+			if ts, err := parseTimestamp(rec.Timestamp); err == nil {
+				result.Prompts = append(result.Prompts, domain.Prompt{
+					ID:        rec.UUID,
+					SessionID: rec.SessionID,
+					Timestamp: ts.UTC(),
+					Text:      text,
+				})
+			}
+		}
 
 		// Only assistant records have usage data
 		if rec.Type != "assistant" {
@@ -62,34 +186,50 @@ func ParseReader(r io.Reader, projectPath string) ParseResult {
 
 		if rec.Message == nil || rec.Message.Usage == nil {
 			result.SkipCount++
+			result.Stats.MissingUsage++
 			continue
 		}
 
-		ts, err := time.Parse(time.RFC3339Nano, rec.Timestamp)
+		ts, err := parseTimestamp(rec.Timestamp)
 		if err != nil {
-			ts, err = time.Parse("2006-01-02T15:04:05.000Z", rec.Timestamp)
-			if err != nil {
-				result.ErrorCount++
-				continue
-			}
+			result.ErrorCount++
+			result.Stats.BadTimestamps++
+			continue
 		}
 
+		usage := rec.Message.Usage
 		entry := domain.UsageEntry{
 			Timestamp:           ts.UTC(),
-			InputTokens:         rec.Message.Usage.InputTokens,
-			OutputTokens:        rec.Message.Usage.OutputTokens,
-			CacheCreationTokens: rec.Message.Usage.CacheCreationInputTokens,
-			CacheReadTokens:     rec.Message.Usage.CacheReadInputTokens,
+			InputTokens:         usage.InputTokens,
+			OutputTokens:        usage.OutputTokens,
+			CacheCreationTokens: usage.CacheCreationInputTokens,
+			CacheReadTokens:     usage.CacheReadInputTokens,
 			Model:               rec.Message.Model,
 			MessageID:           rec.Message.ID,
 			RequestID:           rec.RequestID,
 			SessionID:           rec.SessionID,
 			ProjectPath:         projectPath,
+			Cwd:                 rec.Cwd,
+			GitBranch:           rec.GitBranch,
+			Version:             rec.Version,
+			IsSidechain:         rec.IsSidechain,
+			UserType:            rec.UserType,
+			ServiceTier:         usage.ServiceTier,
+			PromptID:            promptID,
 		}
 
 		if rec.CostUSD != nil {
 			entry.CostUSD = *rec.CostUSD
 		}
+		if usage.ServerToolUse != nil {
+			entry.WebSearchRequests = usage.ServerToolUse.WebSearchRequests
+			entry.WebFetchRequests = usage.ServerToolUse.WebFetchRequests
+		}
+		if usage.CacheCreation != nil {
+			entry.CacheCreation5mTokens = usage.CacheCreation.Ephemeral5mInputTokens
+			entry.CacheCreation1hTokens = usage.CacheCreation.Ephemeral1hInputTokens
+		}
+		entry.Tools = toolNames(rec.Message.Content)
 
 		result.Entries = append(result.Entries, entry)
 	}
@@ -98,5 +238,50 @@ func ParseReader(r io.Reader, projectPath string) ParseResult {
 		result.ErrorCount++
 	}
 
-	return result
+	foldTools(result.Entries)
+	return result, consumed
+}
+
+// parseTimestamp parses a record timestamp.
+func parseTimestamp(s string) (time.Time, error) {
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		ts, err = time.Parse("2006-01-02T15:04:05.000Z", s)
+	}
+	return ts, err
+}
+
+// toolNames returns the names of the tool_use blocks in a message's
+// content, or nil if there are none or the content is not a block list.
+func toolNames(content json.RawMessage) []string {
+	if len(content) == 0 || content[0] != '[' {
+		return nil
+	}
+	var blocks []struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(content, &blocks) != nil {
+		return nil
+	}
+	var names []string
+	for _, b := range blocks {
+		if b.Type == "tool_use" {
+			names = append(names, b.Name)
+		}
+	}
+	return names
+}
+
+// splitCompleteLine is a bufio.SplitFunc body that yields newline-terminated
+// lines. At EOF an unterminated fragment is only yielded if it is already
+// valid JSON; otherwise it is held back as a partial write.
+func splitCompleteLine(data []byte, atEOF bool) (int, []byte) {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, bytes.TrimSuffix(data[:i], []byte{'\r'})
+	}
+	if atEOF && len(data) > 0 && json.Valid(data) {
+		return len(data), data
+	}
+	return 0, nil // need more data, or hold back the fragment
 }
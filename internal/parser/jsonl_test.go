@@ -83,3 +83,147 @@ func TestParseReader_NoUsage(t *testing.T) {
 		t.Errorf("SkipCount = %d, want 1", result.SkipCount)
 	}
 }
+
+func TestParseReader_PartialTrailingLine(t *testing.T) {
+	complete := `{"type":"assistant","timestamp":"2026-02-19T13:56:04.070Z","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"opus","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`
+	partial := `{"type":"assistant","timestamp":"2026-02-19T13:57`
+
+	result, consumed := parseStream(strings.NewReader(complete+"\n"+partial), "/test")
+	if len(result.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(result.Entries))
+	}
+	if result.ErrorCount != 0 {
+		t.Errorf("ErrorCount = %d, want 0 (partial line is held back)", result.ErrorCount)
+	}
+	if consumed != int64(len(complete)+1) {
+		t.Errorf("consumed = %d, want %d", consumed, len(complete)+1)
+	}
+
+	// A complete record that is merely missing its newline is consumed.
+	result, consumed = parseStream(strings.NewReader(complete), "/test")
+	if len(result.Entries) != 1 || consumed != int64(len(complete)) {
+		t.Errorf("unterminated record: got %d entries, consumed %d", len(result.Entries), consumed)
+	}
+}
+
+func TestParseReader_OversizedLine(t *testing.T) {
+	complete := `{"type":"assistant","timestamp":"2026-02-19T13:56:04.070Z","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"opus","usage":{"input_tokens":100,"output_tokens":50,"cache_creation_input_tokens":0,"cache_read_input_tokens":0}}}`
+	huge := `{"type":"user","text":"` + strings.Repeat("x", maxLineSize+1) + `"}`
+	data := huge + "\n" + complete + "\n"
+
+	result, consumed := parseStream(strings.NewReader(data), "/test")
+	if len(result.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1 (the line after the oversized one)", len(result.Entries))
+	}
+	if result.SkipCount != 1 || result.Stats.Oversized != 1 || result.ErrorCount != 0 {
+		t.Errorf("SkipCount = %d, Oversized = %d, ErrorCount = %d, want 1, 1, 0",
+			result.SkipCount, result.Stats.Oversized, result.ErrorCount)
+	}
+	if consumed != int64(len(data)) {
+		t.Errorf("consumed = %d, want %d", consumed, len(data))
+	}
+
+	// An oversized line still being written is held back like a partial one.
+	result, consumed = parseStream(strings.NewReader(complete+"\n"+huge), "/test")
+	if len(result.Entries) != 1 || consumed != int64(len(complete)+1) {
+		t.Errorf("unterminated oversized line: got %d entries, consumed %d", len(result.Entries), consumed)
+	}
+}
+
+func TestParseReader_Metadata(t *testing.T) {
+	input := `{"type":"assistant","timestamp":"2026-02-19T13:56:04.070Z","sessionId":"s1","requestId":"r1","cwd":"/home/me/src/foo","gitBranch":"feature/x","version":"2.0.14","isSidechain":true,"userType":"external","message":{"id":"m1","model":"opus","usage":{"input_tokens":10,"output_tokens":5,"cache_creation_input_tokens":300,"cache_read_input_tokens":0,"service_tier":"standard","server_tool_use":{"web_search_requests":2,"web_fetch_requests":1},"cache_creation":{"ephemeral_5m_input_tokens":100,"ephemeral_1h_input_tokens":200}}}}`
+	result := ParseReader(strings.NewReader(input), "/test")
+	if len(result.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(result.Entries))
+	}
+	e := result.Entries[0]
+	if e.Cwd != "/home/me/src/foo" || e.GitBranch != "feature/x" || e.Version != "2.0.14" {
+		t.Errorf("Cwd/GitBranch/Version = %q/%q/%q", e.Cwd, e.GitBranch, e.Version)
+	}
+	if !e.IsSidechain || e.UserType != "external" || e.ServiceTier != "standard" {
+		t.Errorf("IsSidechain/UserType/ServiceTier = %v/%q/%q", e.IsSidechain, e.UserType, e.ServiceTier)
+	}
+	if e.WebSearchRequests != 2 || e.WebFetchRequests != 1 {
+		t.Errorf("server tool use = %d/%d, want 2/1", e.WebSearchRequests, e.WebFetchRequests)
+	}
+	if e.CacheCreation5mTokens != 100 || e.CacheCreation1hTokens != 200 {
+		t.Errorf("cache creation split = %d/%d, want 100/200", e.CacheCreation5mTokens, e.CacheCreation1hTokens)
+	}
+}
+
+func TestParseReader_Stats(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-02-19T13:56:04.070Z","requestId":"r1","message":{"id":"m1","model":"opus","usage":{"input_tokens":1}}}`,
+		`{"type":"assistant","timestamp":"2026-02-19T13:56:05.000Z","requestId":"r2","message":{"id":"m2","model":"opus"}}`,
+		`{"type":"assistant","timestamp":"yesterday","requestId":"r3","message":{"id":"m3","model":"opus","usage":{"input_tokens":1}}}`,
+		`{"type":"user","timestamp":"2026-02-19T13:55:55.480Z"}`,
+		`{"type":"brand-new","timestamp":"2026-02-19T13:55:55.480Z"}`,
+		`not json`,
+		``,
+	}, "\n")
+
+	st := ParseReader(strings.NewReader(input), "/test").Stats
+	if st.Lines != 6 {
+		t.Errorf("Lines = %d, want 6", st.Lines)
+	}
+	if st.Malformed != 1 || st.MissingUsage != 1 || st.BadTimestamps != 1 {
+		t.Errorf("Malformed/MissingUsage/BadTimestamps = %d/%d/%d, want 1/1/1",
+			st.Malformed, st.MissingUsage, st.BadTimestamps)
+	}
+	if st.Types["assistant"] != 3 || st.Types["brand-new"] != 1 {
+		t.Errorf("Types = %v, want 3 assistant and 1 brand-new", st.Types)
+	}
+
+	sum := st.Add(st)
+	if sum.Lines != 12 || sum.Types["assistant"] != 6 || st.Types["assistant"] != 3 {
+		t.Errorf("Add = %+v; the operands must stay unchanged", sum)
+	}
+}
+
+func TestParseReader_Tools(t *testing.T) {
+	// Claude Code writes one record per content block, repeating the
+	// message id and usage.
+	input := strings.Join([]string{
+		`{"type":"assistant","timestamp":"2026-02-19T13:56:04.070Z","requestId":"r1","message":{"id":"m1","model":"opus","content":[{"type":"text","text":"Let me look."}],"usage":{"input_tokens":10}}}`,
+		`{"type":"assistant","timestamp":"2026-02-19T13:56:05.070Z","requestId":"r1","message":{"id":"m1","model":"opus","content":[{"type":"tool_use","id":"t1","name":"Read","input":{}}],"usage":{"input_tokens":10}}}`,
+		`{"type":"assistant","timestamp":"2026-02-19T13:56:06.070Z","requestId":"r1","message":{"id":"m1","model":"opus","content":[{"type":"tool_use","id":"t2","name":"Bash","input":{}},{"type":"tool_use","id":"t3","name":"Read","input":{}}],"usage":{"input_tokens":10}}}`,
+	}, "\n")
+
+	result := ParseReader(strings.NewReader(input), "/test")
+	if len(result.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(result.Entries))
+	}
+	if got := strings.Join(result.Entries[0].Tools, ","); got != "Read,Bash,Read" {
+		t.Errorf("first record Tools = %q, want %q", got, "Read,Bash,Read")
+	}
+	if len(result.Entries[1].Tools)+len(result.Entries[2].Tools) != 0 {
+		t.Errorf("repeated records kept their tools: %v, %v", result.Entries[1].Tools, result.Entries[2].Tools)
+	}
+}
+
+func TestParseReader_Prompts(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"user","uuid":"u1","parentUuid":null,"timestamp":"2026-02-19T13:56:00.000Z","sessionId":"s1","message":{"role":"user","content":"fix the\n  flaky test"}}`,
+		`{"type":"assistant","uuid":"a1","parentUuid":"u1","timestamp":"2026-02-19T13:56:04.070Z","sessionId":"s1","requestId":"r1","message":{"id":"m1","model":"opus","usage":{"input_tokens":10}}}`,
+		`{"type":"user","uuid":"u2","parentUuid":"a1","timestamp":"2026-02-19T13:56:05.000Z","sessionId":"s1","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"t1","content":"ok"}]}}`,
+		`{"type":"assistant","uuid":"a2","parentUuid":"u2","timestamp":"2026-02-19T13:56:09.070Z","sessionId":"s1","requestId":"r2","message":{"id":"m2","model":"opus","usage":{"input_tokens":10}}}`,
+		`{"type":"user","uuid":"u3","parentUuid":"a2","isMeta":true,"timestamp":"2026-02-19T13:57:00.000Z","sessionId":"s1","message":{"role":"user","content":"Caveat: ..."}}`,
+		`{"type":"user","uuid":"u4","parentUuid":"u3","timestamp":"2026-02-19T13:57:01.000Z","sessionId":"s1","message":{"role":"user","content":[{"type":"text","text":"now commit"}]}}`,
+		`{"type":"assistant","uuid":"a3","parentUuid":"u4","timestamp":"2026-02-19T13:57:04.070Z","sessionId":"s1","requestId":"r3","message":{"id":"m3","model":"opus","usage":{"input_tokens":10}}}`,
+		`{"type":"assistant","uuid":"a4","parentUuid":"elsewhere","timestamp":"2026-02-19T13:57:05.070Z","sessionId":"s1","requestId":"r4","message":{"id":"m4","model":"opus","usage":{"input_tokens":10}}}`,
+	}, "\n")
+
+	result := ParseReader(strings.NewReader(input), "/test")
+	if len(result.Prompts) != 2 {
+		t.Fatalf("got %d prompts, want 2 (tool results and meta records are not prompts)", len(result.Prompts))
+	}
+	if p := result.Prompts[0]; p.ID != "u1" || p.Text != "fix the flaky test" || p.SessionID != "s1" {
+		t.Errorf("first prompt = %+v", p)
+	}
+	want := []string{"u1", "u1", "u4", "u4"} // the last one falls back to the session's latest prompt
+	for i, e := range result.Entries {
+		if e.PromptID != want[i] {
+			t.Errorf("entry %d PromptID = %q, want %q", i, e.PromptID, want[i])
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPromptPreview caps the prompt text kept per prompt, in runes.
+const maxPromptPreview = 200
+
+// promptChain follows parentUuid links to attribute every record to the
+// user prompt that started it.
+type promptChain struct {
+	prompt map[string]string // record uuid -> prompt uuid
+	last   map[string]string // session -> latest prompt uuid
+}
+
+func newPromptChain() *promptChain {
+	return &promptChain{prompt: make(map[string]string), last: make(map[string]string)}
+}
+
+// link records a record's place in the chain and returns the uuid of its
+// prompt: its own for a prompt, else its parent's. A record whose parent is
+// unknown, such as the first message of a subagent, belongs to the latest
+// prompt of its session; "" if there is none in this parse.
+func (c *promptChain) link(rec *rawRecord, isPrompt bool) string {
+	var id string
+	switch {
+	case isPrompt:
+		id = rec.UUID
+		c.last[rec.SessionID] = id
+	case rec.ParentUUID != "" && c.prompt[rec.ParentUUID] != "":
+		id = c.prompt[rec.ParentUUID]
+	default:
+		id = c.last[rec.SessionID]
+	}
+	if rec.UUID != "" && id != "" {
+		c.prompt[rec.UUID] = id
+	}
+	return id
+}
+
+// promptText returns the text of a user record that is a prompt typed by
+// the user. Tool results, meta records and subagent instructions are not
+// prompts.
+func promptText(rec *rawRecord) (string, bool) {
+	if rec.Type != "user" || rec.IsMeta || rec.IsSidechain || rec.UUID == "" || rec.Message == nil {
+		return "", false
+	}
+	content := rec.Message.Content
+	if len(content) == 0 {
+		return "", false
+	}
+	if content[0] == '"' {
+		var text string
+		if json.Unmarshal(content, &text) != nil {
+			return "", false
+		}
+		return preview(text), true
+	}
+
+	var blocks []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(content, &blocks) != nil {
+		return "", false
+	}
+	var texts []string
+	for _, b := range blocks {
+		switch b.Type {
+		case "tool_result":
+			return "", false
+		case "text":
+			texts = append(texts, b.Text)
+		}
+	}
+	if len(texts) == 0 {
+		return "", false
+	}
+	return preview(strings.Join(texts, " ")), true
+}
+
+// preview collapses whitespace and truncates text to maxPromptPreview
+// runes.
+func preview(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= maxPromptPreview {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:maxPromptPreview-1]) + "…"
+}
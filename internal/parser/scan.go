@@ -6,6 +6,8 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
 
 	"github.com/anomredux/claude-smi/internal/domain"
 )
@@ -24,10 +26,132 @@ func (DefaultScanner) ScanAndParse(ctx context.Context, dataDir string) []domain
 	return ScanAndParse(ctx, dataDir)
 }
 
+// ScanOptions controls how a scan parses files.
+type ScanOptions struct {
+	Workers int // parallel file parsers; <= 0 means runtime.GOMAXPROCS(0)
+}
+
+// workers returns the effective worker count for n jobs.
+func (o ScanOptions) workers(n int) int {
+	w := o.Workers
+	if w <= 0 {
+		w = runtime.GOMAXPROCS(0)
+	}
+	if w > n {
+		w = n
+	}
+	return max(w, 1)
+}
+
+// FileResult is the parse outcome of a single file.
+type FileResult struct {
+	Path   string
+	Source string // name of the data directory the file was found in
+	ParseResult
+}
+
+// ScanResult holds per-file parse results in path order.
+type ScanResult struct {
+	Files []FileResult
+}
+
+// Entries returns the combined entries of all files.
+func (r ScanResult) Entries() []domain.UsageEntry {
+	total := 0
+	for _, f := range r.Files {
+		total += len(f.Entries)
+	}
+	all := make([]domain.UsageEntry, 0, total)
+	for _, f := range r.Files {
+		all = append(all, f.Entries...)
+	}
+	return all
+}
+
+// Prompts returns the combined prompts of all files.
+func (r ScanResult) Prompts() []domain.Prompt {
+	var all []domain.Prompt
+	for _, f := range r.Files {
+		all = append(all, f.Prompts...)
+	}
+	return all
+}
+
+// Totals returns the summed skip and error counts of all files.
+func (r ScanResult) Totals() (skipped, errors int) {
+	for _, f := range r.Files {
+		skipped += f.SkipCount
+		errors += f.ErrorCount
+	}
+	return skipped, errors
+}
+
 // ScanAndParse walks the data directory, parses all .jsonl files,
 // and returns the combined usage entries.
 func ScanAndParse(ctx context.Context, dataDir string) []domain.UsageEntry {
-	// First pass: collect file paths using WalkDir (avoids unnecessary Stat calls)
+	return Scan(ctx, dataDir, ScanOptions{}).Entries()
+}
+
+// Scan walks the data directory and parses all .jsonl files with a bounded
+// worker pool. Results are ordered by path regardless of completion order.
+// Files not yet parsed when ctx is cancelled are omitted.
+func Scan(ctx context.Context, dataDir string, opts ScanOptions) ScanResult {
+	var sr ScanResult
+	ScanEach(ctx, []string{dataDir}, opts, func(f FileResult) {
+		sr.Files = append(sr.Files, f)
+	})
+	return sr
+}
+
+// ScanEach parses every .jsonl file under the data directories with a
+// bounded worker pool and passes each result to fn, directory by directory
+// in path order, without retaining it. Only a few batches of files are in
+// memory at once. Entries are tagged with the SourceName of their
+// directory; a file under several directories is parsed once.
+func ScanEach(ctx context.Context, dataDirs []string, opts ScanOptions, fn func(FileResult)) {
+	type job struct{ path, source string }
+	seen := make(map[string]struct{})
+	var jobs []job
+	for _, dir := range dataDirs {
+		source := SourceName(dir)
+		for _, path := range collectPaths(ctx, dir) {
+			if _, dup := seen[path]; !dup {
+				seen[path] = struct{}{}
+				jobs = append(jobs, job{path, source})
+			}
+		}
+	}
+
+	workers := opts.workers(len(jobs))
+	batch := 4 * workers
+	for start := 0; start < len(jobs) && ctx.Err() == nil; start += batch {
+		chunk := jobs[start:min(start+batch, len(jobs))]
+		results := make([]FileResult, len(chunk))
+		done := make([]bool, len(chunk))
+		parallel(ctx, len(chunk), workers, func(i int) {
+			f, err := os.Open(chunk[i].path)
+			if err != nil {
+				return
+			}
+			defer f.Close()
+			res := ParseReader(f, filepath.Dir(chunk[i].path))
+			for k := range res.Entries {
+				res.Entries[k].Source = chunk[i].source
+			}
+			results[i] = FileResult{Path: chunk[i].path, Source: chunk[i].source, ParseResult: res}
+			done[i] = true
+		})
+		for i, ok := range done {
+			if ok {
+				fn(results[i])
+			}
+		}
+	}
+}
+
+// collectPaths returns all .jsonl files under dataDir in lexical order.
+// WalkDir avoids unnecessary Stat calls.
+func collectPaths(ctx context.Context, dataDir string) []string {
 	var paths []string
 	_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
 		if ctx.Err() != nil {
@@ -39,38 +163,59 @@ func ScanAndParse(ctx context.Context, dataDir string) []domain.UsageEntry {
 		paths = append(paths, path)
 		return nil
 	})
+	return paths
+}
 
-	// Pre-allocate with reasonable estimate (avg ~50 entries per file)
-	all := make([]domain.UsageEntry, 0, len(paths)*50)
+// parallel runs fn(0..n-1) on the given number of workers. Jobs that have
+// not started when ctx is cancelled are skipped. fn must only write to
+// per-index state.
+func parallel(ctx context.Context, n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				fn(i)
+			}
+		}()
+	}
 
-	for _, path := range paths {
+	for i := 0; i < n; i++ {
 		if ctx.Err() != nil {
 			break
 		}
-
-		f, err := os.Open(path)
-		if err != nil {
-			continue
-		}
-
-		projectPath := filepath.Dir(path)
-		result := ParseReader(f, projectPath)
-		all = append(all, result.Entries...)
-		f.Close()
+		jobs <- i
 	}
-
-	return all
+	close(jobs)
+	wg.Wait()
 }
 
 // FileChange describes a file that has changed since the last read.
 type FileChange struct {
 	Path   string
 	Offset int64
+	Inode  uint64 // inode the offset was recorded for; 0 if unknown
+	Source string // name entries of this file are tagged with
 }
 
 // ParseIncremental reads only the new data from each changed file (from the
 // given offset) and returns the new entries along with updated offsets.
+// Offsets always stop at a line boundary, so a line that is still being
+// written is re-read on the next pass. A file that shrank below its offset
+// or was replaced (different inode) is re-read from the start.
 func ParseIncremental(ctx context.Context, changes []FileChange) (entries []domain.UsageEntry, newOffsets map[string]int64) {
+	result, newOffsets := ParseChanges(ctx, changes)
+	return result.Entries, newOffsets
+}
+
+// ParseChanges is ParseIncremental returning the combined parse result,
+// including new prompts and stats, instead of only the entries.
+func ParseChanges(ctx context.Context, changes []FileChange) (combined ParseResult, newOffsets map[string]int64) {
 	newOffsets = make(map[string]int64, len(changes))
 
 	for _, fc := range changes {
@@ -83,25 +228,35 @@ func ParseIncremental(ctx context.Context, changes []FileChange) (entries []doma
 			continue
 		}
 
+		offset := fc.Offset
+		if info, err := f.Stat(); err == nil {
+			replaced := fc.Inode != 0 && FileInode(info) != fc.Inode
+			if replaced || info.Size() < offset {
+				offset = 0
+			}
+		}
+
 		// Seek to the last known offset
-		if fc.Offset > 0 {
-			if _, err := f.Seek(fc.Offset, io.SeekStart); err != nil {
+		if offset > 0 {
+			if _, err := f.Seek(offset, io.SeekStart); err != nil {
 				f.Close()
 				continue
 			}
 		}
 
 		projectPath := filepath.Dir(fc.Path)
-		result := ParseReader(f, projectPath)
-		entries = append(entries, result.Entries...)
-
-		// Record the new offset
-		pos, err := f.Seek(0, io.SeekCurrent)
-		if err == nil {
-			newOffsets[fc.Path] = pos
+		result, n := parseStream(f, projectPath)
+		for k := range result.Entries {
+			result.Entries[k].Source = fc.Source
 		}
+		combined.Entries = append(combined.Entries, result.Entries...)
+		combined.Prompts = append(combined.Prompts, result.Prompts...)
+		combined.SkipCount += result.SkipCount
+		combined.ErrorCount += result.ErrorCount
+		combined.Stats = combined.Stats.Add(result.Stats)
+		newOffsets[fc.Path] = offset + n
 		f.Close()
 	}
 
-	return entries, newOffsets
+	return combined, newOffsets
 }
@@ -87,6 +87,34 @@ func TestScanAndParse_IgnoresNonJSONL(t *testing.T) {
 	}
 }
 
+func TestScan_PerFileResults(t *testing.T) {
+	dir := testdataDir(t)
+	ctx := context.Background()
+
+	for _, workers := range []int{1, 4} {
+		result := Scan(ctx, dir, ScanOptions{Workers: workers})
+		if len(result.Files) != 2 {
+			t.Fatalf("workers=%d: got %d files, want 2", workers, len(result.Files))
+		}
+		// Results are ordered by path regardless of worker scheduling.
+		if filepath.Base(filepath.Dir(result.Files[0].Path)) != "project-a" {
+			t.Errorf("workers=%d: first file = %s, want project-a", workers, result.Files[0].Path)
+		}
+		if got := len(result.Files[0].Entries); got != 2 {
+			t.Errorf("workers=%d: project-a entries = %d, want 2", workers, got)
+		}
+		if got := result.Files[0].SkipCount; got != 1 { // user record
+			t.Errorf("workers=%d: project-a SkipCount = %d, want 1", workers, got)
+		}
+		if skipped, errs := result.Totals(); skipped != 1 || errs != 0 {
+			t.Errorf("workers=%d: Totals() = (%d, %d), want (1, 0)", workers, skipped, errs)
+		}
+		if got := len(result.Entries()); got != 3 {
+			t.Errorf("workers=%d: Entries() = %d, want 3", workers, got)
+		}
+	}
+}
+
 func TestParseIncremental(t *testing.T) {
 	dir := testdataDir(t)
 	ctx := context.Background()
@@ -147,3 +175,94 @@ func TestParseIncremental_NonExistentFile(t *testing.T) {
 		t.Errorf("got %d offsets, want 0 for nonexistent file", len(offsets))
 	}
 }
+
+func TestParseIncremental_HoldsBackPartialLine(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	half := indexLine2[:40]
+	writeLog(t, logPath, indexLine1+"\n"+half)
+
+	ctx := context.Background()
+	entries, offsets := ParseIncremental(ctx, []FileChange{{Path: logPath}})
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if offsets[logPath] != int64(len(indexLine1)+1) {
+		t.Fatalf("offset = %d, want end of first line %d", offsets[logPath], len(indexLine1)+1)
+	}
+
+	// Finish writing the line; the next pass must pick up the whole record.
+	appendLog(t, logPath, indexLine2[40:]+"\n")
+	entries, _ = ParseIncremental(ctx, []FileChange{{Path: logPath, Offset: offsets[logPath]}})
+	if len(entries) != 1 || entries[0].MessageID != "m2" {
+		t.Errorf("second pass got %+v, want m2", entries)
+	}
+}
+
+func TestParseIncremental_TruncatedFile(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n")
+
+	ctx := context.Background()
+	// Offset beyond the current size: the file was truncated and rewritten.
+	entries, offsets := ParseIncremental(ctx, []FileChange{{Path: logPath, Offset: 10_000}})
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1 (re-read from zero)", len(entries))
+	}
+	if offsets[logPath] != int64(len(indexLine1)+1) {
+		t.Errorf("offset = %d, want %d", offsets[logPath], len(indexLine1)+1)
+	}
+}
+
+func TestParseIncremental_ReplacedFile(t *testing.T) {
+	dataDir := t.TempDir()
+	logPath := filepath.Join(dataDir, "proj", "log.jsonl")
+	writeLog(t, logPath, indexLine1+"\n"+indexLine2+"\n")
+
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inode := FileInode(info)
+	if inode == 0 {
+		t.Skip("inode not available on this platform")
+	}
+
+	// Same size, but recorded for a different inode.
+	ctx := context.Background()
+	entries, _ := ParseIncremental(ctx, []FileChange{{Path: logPath, Offset: int64(len(indexLine1) + 1), Inode: inode + 1}})
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want 2 (re-read from zero)", len(entries))
+	}
+}
+
+func TestScanEach(t *testing.T) {
+	base := t.TempDir()
+	work := filepath.Join(base, "work", "projects")
+	home := filepath.Join(base, "home", "projects")
+	for i := 0; i < 10; i++ {
+		writeLog(t, filepath.Join(work, "p", string(rune('a'+i))+".jsonl"), indexLine1+"\n")
+	}
+	writeLog(t, filepath.Join(home, "p", "z.jsonl"), indexLine2+"\n")
+
+	var paths []string
+	ScanEach(context.Background(), []string{work, home, work}, ScanOptions{Workers: 2}, func(f FileResult) {
+		paths = append(paths, f.Path)
+		if f.Source != SourceName(filepath.Dir(filepath.Dir(f.Path))) {
+			t.Errorf("%s Source = %q", f.Path, f.Source)
+		}
+	})
+
+	if len(paths) != 11 {
+		t.Fatalf("got %d files, want 11 (a directory listed twice is scanned once)", len(paths))
+	}
+	for i := 1; i < 10; i++ {
+		if paths[i-1] >= paths[i] {
+			t.Errorf("files out of path order: %s before %s", paths[i-1], paths[i])
+		}
+	}
+	if paths[10] != filepath.Join(home, "p", "z.jsonl") {
+		t.Errorf("last file = %s, want the second directory's", paths[10])
+	}
+}
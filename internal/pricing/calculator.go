@@ -72,3 +72,18 @@ func (c *Calculator) CacheSavings(e *domain.UsageEntry) float64 {
 	}
 	return float64(e.CacheReadTokens) * (pricing.Input - pricing.CacheRead) / 1_000_000
 }
+
+// CacheRates returns the rates that decide what prompt caching saves on
+// model. The table has no 1-hour write rate; it is twice the input rate.
+func (c *Calculator) CacheRates(model string) (domain.CacheRates, bool) {
+	pricing, ok := c.table.Lookup(model)
+	if !ok {
+		return domain.CacheRates{}, false
+	}
+	return domain.CacheRates{
+		Input:   pricing.Input,
+		Write5m: pricing.CacheCreation,
+		Write1h: 2 * pricing.Input,
+		Read:    pricing.CacheRead,
+	}, true
+}
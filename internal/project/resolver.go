@@ -0,0 +1,204 @@
+// Package project maps the encoded per-project log directories Claude Code
+// writes (e.g. ~/.claude/projects/-home-me-src-foo) to real project roots
+// and human-readable display names.
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+// Project is a resolved project: the repository (or directory) root that
+// groups its worktrees and subdirectories, and its display name.
+type Project struct {
+	Root string
+	Name string
+}
+
+// Resolver resolves entries to projects. Results are cached because
+// resolution touches the file system. It is safe for concurrent use.
+type Resolver struct {
+	aliases map[string]string // root -> display name
+
+	mu    sync.Mutex
+	cache map[string]Project // working directory -> project
+}
+
+// NewResolver returns a resolver using the given root -> name aliases.
+// Alias keys may start with "~/".
+func NewResolver(aliases map[string]string) *Resolver {
+	r := &Resolver{
+		aliases: make(map[string]string, len(aliases)),
+		cache:   make(map[string]Project),
+	}
+	for root, name := range aliases {
+		r.aliases[filepath.Clean(expandHome(root))] = name
+	}
+	return r
+}
+
+// Apply sets ProjectPath to the resolved root and ProjectName to the
+// display name of every entry. Entries that already carry a name are left
+// alone, so applying twice is harmless.
+func (r *Resolver) Apply(entries []domain.UsageEntry) {
+	for i := range entries {
+		r.ApplyEntry(&entries[i])
+	}
+}
+
+// ApplyEntry is Apply for a single entry.
+func (r *Resolver) ApplyEntry(e *domain.UsageEntry) {
+	if e.ProjectName != "" {
+		return
+	}
+	p := r.Resolve(*e)
+	e.ProjectPath = p.Root
+	e.ProjectName = p.Name
+}
+
+// Resolve returns the project of a single entry. The working directory
+// recorded in the transcript wins; otherwise the encoded log directory
+// name is decoded.
+func (r *Resolver) Resolve(e domain.UsageEntry) Project {
+	dir := e.Cwd
+	if dir == "" {
+		dir = e.ProjectPath
+		if base := filepath.Base(dir); isEncoded(base) {
+			dir = DecodeDirName(base)
+		}
+	}
+	if dir == "" {
+		return Project{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if p, ok := r.cache[dir]; ok {
+		return p
+	}
+	root := RepoRoot(dir)
+	p := Project{Root: root, Name: r.aliases[root]}
+	if p.Name == "" {
+		p.Name = filepath.Base(root)
+	}
+	r.cache[dir] = p
+	return p
+}
+
+// RepoRoot returns the root of the git repository containing dir, with
+// linked worktrees mapped to their main repository. If dir is not inside
+// a repository (or no longer exists) it is returned unchanged.
+func RepoRoot(dir string) string {
+	dir = filepath.Clean(dir)
+	for cur := dir; ; {
+		gitPath := filepath.Join(cur, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return cur
+			}
+			if main := worktreeMain(gitPath); main != "" {
+				return main
+			}
+			return cur // submodule or unknown .git file
+		}
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			return dir
+		}
+		cur = parent
+	}
+}
+
+// worktreeMain reads a ".git" file ("gitdir: /repo/.git/worktrees/name")
+// and returns the main repository root, or "" if it is not a worktree.
+func worktreeMain(gitFile string) string {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return ""
+	}
+	gitdir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitdir = filepath.Clean(strings.TrimSpace(gitdir))
+	if !filepath.IsAbs(gitdir) {
+		gitdir = filepath.Join(filepath.Dir(gitFile), gitdir)
+	}
+	sep := string(filepath.Separator)
+	marker := sep + ".git" + sep + "worktrees" + sep
+	if i := strings.Index(gitdir, marker); i >= 0 {
+		return gitdir[:i]
+	}
+	return ""
+}
+
+// drivePrefix matches an encoded Windows drive such as "C--".
+var drivePrefix = regexp.MustCompile(`^[A-Za-z]--`)
+
+// isEncoded reports whether a directory name looks like one of Claude
+// Code's encoded project paths.
+func isEncoded(name string) bool {
+	return strings.HasPrefix(name, "-") || drivePrefix.MatchString(name)
+}
+
+// DecodeDirName turns an encoded project directory name back into a path.
+// Claude Code replaces every path separator and dot with "-", which is
+// ambiguous for names containing hyphens, so existing directories are
+// preferred when choosing where the separators were. Segments that do not
+// exist on disk fall back to splitting on every "-".
+func DecodeDirName(name string) string {
+	root := string(filepath.Separator)
+	rest := strings.TrimPrefix(name, "-")
+	if drivePrefix.MatchString(name) {
+		root = name[:1] + ":" + string(filepath.Separator)
+		rest = name[3:]
+	}
+
+	tokens := strings.Split(rest, "-")
+	// An empty token marks a dot that followed a separator ("/." -> "--").
+	var parts []string
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == "" && i+1 < len(tokens) {
+			tokens[i+1] = "." + tokens[i+1]
+			continue
+		}
+		parts = append(parts, tokens[i])
+	}
+
+	path := root
+	for i := 0; i < len(parts); {
+		j, segment := longestExisting(path, parts, i)
+		path = filepath.Join(path, segment)
+		i = j
+	}
+	return path
+}
+
+// longestExisting finds the longest run parts[i:j] that, joined by "-" or
+// ".", names an existing entry under base. It returns the end index and
+// the segment name, or i+1 and parts[i] when nothing matches.
+func longestExisting(base string, parts []string, i int) (int, string) {
+	for j := len(parts); j > i+1; j-- {
+		for _, sep := range []string{"-", "."} {
+			segment := strings.Join(parts[i:j], sep)
+			if _, err := os.Stat(filepath.Join(base, segment)); err == nil {
+				return j, segment
+			}
+		}
+	}
+	return i + 1, parts[i]
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,110 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anomredux/claude-smi/internal/domain"
+)
+
+func mkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// encode mimics how Claude Code names per-project log directories.
+func encode(path string) string {
+	return strings.NewReplacer(string(filepath.Separator), "-", ".", "-").Replace(path)
+}
+
+func TestRepoRoot(t *testing.T) {
+	base := t.TempDir()
+	repo := filepath.Join(base, "repo")
+	mkdir(t, filepath.Join(repo, ".git", "worktrees", "feature"))
+	mkdir(t, filepath.Join(repo, "cmd", "tool"))
+
+	worktree := filepath.Join(base, "repo-feature")
+	mkdir(t, worktree)
+	gitFile := "gitdir: " + filepath.Join(repo, ".git", "worktrees", "feature") + "\n"
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte(gitFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := filepath.Join(base, "scratch")
+	mkdir(t, plain)
+
+	tests := []struct {
+		dir, want string
+	}{
+		{repo, repo},
+		{filepath.Join(repo, "cmd", "tool"), repo},
+		{worktree, repo},
+		{plain, plain},
+	}
+	for _, tt := range tests {
+		if got := RepoRoot(tt.dir); got != tt.want {
+			t.Errorf("RepoRoot(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeDirName(t *testing.T) {
+	base := t.TempDir()
+	hyphenated := filepath.Join(base, "my-app")
+	dotted := filepath.Join(base, ".config", "site.io")
+	mkdir(t, hyphenated)
+	mkdir(t, dotted)
+
+	for _, want := range []string{hyphenated, dotted} {
+		if got := DecodeDirName(encode(want)); got != want {
+			t.Errorf("DecodeDirName(%q) = %q, want %q", encode(want), got, want)
+		}
+	}
+
+	// Paths that no longer exist fall back to splitting on every "-".
+	gone := filepath.Join(base, "gone", "x")
+	if got := DecodeDirName(encode(filepath.Join(base, "gone-x"))); got != gone {
+		t.Errorf("DecodeDirName(missing) = %q, want %q", got, gone)
+	}
+}
+
+func TestResolver_Apply(t *testing.T) {
+	base := t.TempDir()
+	repo := filepath.Join(base, "my-app")
+	mkdir(t, filepath.Join(repo, ".git"))
+	mkdir(t, filepath.Join(repo, "web"))
+	other := filepath.Join(base, "other")
+	mkdir(t, filepath.Join(other, ".git"))
+
+	logs := filepath.Join(base, "logs")
+	entries := []domain.UsageEntry{
+		{Cwd: filepath.Join(repo, "web"), ProjectPath: filepath.Join(logs, "ignored")},
+		{ProjectPath: filepath.Join(logs, encode(repo))},
+		{Cwd: other},
+	}
+
+	r := NewResolver(map[string]string{other: "Other Project"})
+	r.Apply(entries)
+
+	want := []Project{
+		{Root: repo, Name: "my-app"},
+		{Root: repo, Name: "my-app"},
+		{Root: other, Name: "Other Project"},
+	}
+	for i, w := range want {
+		if entries[i].ProjectPath != w.Root || entries[i].ProjectName != w.Name {
+			t.Errorf("entry %d = (%q, %q), want (%q, %q)",
+				i, entries[i].ProjectPath, entries[i].ProjectName, w.Root, w.Name)
+		}
+	}
+
+	// Applying again must not re-resolve the already resolved roots.
+	r.Apply(entries)
+	if entries[0].ProjectPath != repo {
+		t.Errorf("second Apply changed ProjectPath to %q", entries[0].ProjectPath)
+	}
+}
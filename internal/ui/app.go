@@ -6,10 +6,14 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/anomredux/claude-smi/internal/api"
+	"github.com/anomredux/claude-smi/internal/archive"
 	"github.com/anomredux/claude-smi/internal/config"
 	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/history"
 	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/parser"
 	"github.com/anomredux/claude-smi/internal/pricing"
+	"github.com/anomredux/claude-smi/internal/project"
 	"github.com/anomredux/claude-smi/internal/ui/overlays"
 	"github.com/anomredux/claude-smi/internal/ui/views"
 )
@@ -20,6 +24,12 @@ const (
 	ViewLive ViewType = iota
 	ViewBlocks
 	ViewDailyReport
+	ViewTools
+	ViewPrompts
+	ViewWeekly
+	ViewSessions
+	ViewProjects
+	ViewCache
 	ViewCount // sentinel: number of views
 )
 
@@ -47,8 +57,9 @@ type BlinkMsg time.Time
 
 // dataLoadedMsg carries freshly parsed data from a full scan.
 type dataLoadedMsg struct {
-	entries []domain.UsageEntry
-	offsets map[string]int64
+	entries   []domain.UsageEntry
+	prompts   []domain.Prompt
+	positions map[string]parser.FileChange
 }
 
 // apiUsageMsg carries usage data fetched from the OAuth API.
@@ -65,8 +76,9 @@ type pricingMsg struct {
 
 // incrementalLoadedMsg carries new entries parsed incrementally.
 type incrementalLoadedMsg struct {
-	entries []domain.UsageEntry
-	offsets map[string]int64
+	entries   []domain.UsageEntry
+	prompts   []domain.Prompt
+	positions map[string]parser.FileChange
 }
 
 type App struct {
@@ -77,6 +89,12 @@ type App struct {
 	liveView        *views.LiveView
 	blocksView      *views.BlocksView
 	dailyReportView *views.DailyReportView
+	toolsView       *views.ToolsView
+	promptsView     *views.PromptsView
+	weeklyView      *views.WeeklyView
+	sessionsView    *views.SessionsView
+	projectsView    *views.ProjectsView
+	cacheView       *views.CacheView
 
 	// Overlays
 	helpOverlay     *overlays.HelpOverlay
@@ -87,6 +105,7 @@ type App struct {
 	filteredEntries []domain.UsageEntry
 	blocks          []domain.SessionBlock
 	daily           []domain.DailyAggregate
+	prompts         []domain.Prompt // user prompts of all parsed logs
 	Config          config.Config
 	calc            *pricing.Calculator
 	tz              *time.Location
@@ -96,19 +115,40 @@ type App struct {
 	animTick uint
 
 	// Project filter
-	projects       []string        // available project paths
+	projects       []string          // available project roots
+	projectNames   map[string]string // project root -> display name
+	resolver       *project.Resolver
 	activeProjects map[string]bool // selected projects; empty = all
 	projectPicking bool            // project picker active
 	projectCursor  int
 	projectScroll  int
 
+	// Source filter
+	sources      []string // available data source names
+	activeSource string   // selected source; empty = all
+
+	// Date range resolved from SinceFilter and UntilFilter
+	dateRange domain.TimeRange
+
+	// Filter expression
+	filter        *domain.FilterExpr // nil = no filter
+	filterEditing bool               // filter prompt active
+	filterInput   string
+	filterErr     string // why filterInput does not compile
+
 	// Notifications
 	notifications *NotificationManager
+	announced     map[string]bool    // anomalies already notified, by kind and key
+	budgetAlerts  map[string]float64 // highest threshold notified, by budget and period
 
 	// Data
-	DataDir     string
-	SinceFilter string // YYYY-MM-DD
-	UntilFilter string // YYYY-MM-DD
+	DataDirs    []string       // Claude data directories; empty = auto-discover
+	IndexPath   string         // persistent parse index; empty disables caching
+	Archive     *archive.Store // usage history beyond log retention; nil disables
+	Windows     *history.Store // observed API windows that anchor blocks; nil disables
+	SinceFilter string         // range bound, see domain.RangeContext
+	UntilFilter string         // range bound, see domain.RangeContext
+	MetaFilter  domain.MetadataFilter
 
 	// Terminal
 	width  int
@@ -117,9 +157,9 @@ type App struct {
 	// Scroll state — pointer so View() (value receiver) mutations persist.
 	scroll *scrollState
 
-	// Incremental parsing state
-	fileOffsets   map[string]int64
-	fileOffsetsMu *sync.Mutex
+	// Incremental parsing state: parsed offset and inode per file
+	filePositions   map[string]parser.FileChange
+	filePositionsMu *sync.Mutex
 	initialLoaded bool // true after first full scan
 
 	// State
@@ -149,13 +189,23 @@ func NewApp(cfg config.Config) App {
 		calc:            calc,
 		scroll:          &scrollState{},
 		activeProjects:  make(map[string]bool),
-		fileOffsets:     make(map[string]int64),
-		fileOffsetsMu:   &sync.Mutex{},
+		projectNames:    make(map[string]string),
+		resolver:        project.NewResolver(cfg.Projects.Aliases),
+		filePositions:   make(map[string]parser.FileChange),
+		filePositionsMu: &sync.Mutex{},
 		liveView:        views.NewLiveView(tz, calc),
 		blocksView:      views.NewBlocksView(tz),
 		dailyReportView: views.NewDailyReportView(tz),
+		toolsView:       views.NewToolsView(tz),
+		promptsView:     views.NewPromptsView(tz),
+		weeklyView:      views.NewWeeklyView(tz),
+		sessionsView:    views.NewSessionsView(tz),
+		projectsView:    views.NewProjectsView(tz),
+		cacheView:       views.NewCacheView(tz),
 		helpOverlay:     overlays.NewHelpOverlay(),
 		notifications:   NewNotificationManager(cfg.Notifications.Bell),
+		announced:       make(map[string]bool),
+		budgetAlerts:    make(map[string]float64),
 	}
 }
 
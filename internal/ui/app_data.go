@@ -3,93 +3,280 @@ package ui
 import (
 	"context"
 	"io/fs"
-	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/anomredux/claude-smi/internal/api"
+	"github.com/anomredux/claude-smi/internal/archive"
+	"github.com/anomredux/claude-smi/internal/config"
 	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/history"
+	"github.com/anomredux/claude-smi/internal/i18n"
 	"github.com/anomredux/claude-smi/internal/parser"
 	"github.com/anomredux/claude-smi/internal/pricing"
 )
 
-// loadData performs a full scan of all JSONL files and records file sizes
-// as offsets for subsequent incremental loads.
+// loadData performs a full scan of all JSONL files through the persistent
+// parse index and records parsed positions for subsequent incremental loads.
 func (a App) loadData() tea.Msg {
-	dataDir := a.DataDir
-	if dataDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return dataLoadedMsg{}
-		}
-		dataDir = filepath.Join(home, ".claude", "projects")
-	}
-
 	ctx := context.Background()
-	entries := parser.ScanAndParse(ctx, dataDir)
+	idx := parser.NewIndex()
+	if a.IndexPath != "" {
+		idx = parser.LoadIndex(a.IndexPath)
+	}
+	opts := parser.ScanOptions{Workers: a.Config.General.ParseWorkers}
+	scan := idx.Scan(ctx, a.dataDirs(), opts)
+	entries := scan.Entries()
+	if a.IndexPath != "" {
+		_ = idx.Save(a.IndexPath)
+	}
 
-	// Record file sizes as offsets for incremental parsing
-	offsets := make(map[string]int64)
-	_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
-			return nil
+	// Restore history pruned from the logs and archive anything new.
+	if a.Archive != nil {
+		if archived, err := a.Archive.Load(); err == nil {
+			entries = archive.Merge(entries, archived)
+			_, _ = a.Archive.Append(entries)
 		}
-		if info, err := d.Info(); err == nil {
-			offsets[path] = info.Size()
-		}
-		return nil
-	})
+	}
 
-	return dataLoadedMsg{entries: entries, offsets: offsets}
+	return dataLoadedMsg{entries: entries, prompts: scan.Prompts(), positions: idx.Positions()}
 }
 
 // loadIncremental scans for files that have grown since the last read
 // and parses only the new data.
 func (a App) loadIncremental() tea.Msg {
-	dataDir := a.DataDir
-	if dataDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return incrementalLoadedMsg{}
-		}
-		dataDir = filepath.Join(home, ".claude", "projects")
-	}
-
 	// Build a list of changed files
-	a.fileOffsetsMu.Lock()
-	currentOffsets := make(map[string]int64, len(a.fileOffsets))
-	for k, v := range a.fileOffsets {
-		currentOffsets[k] = v
+	a.filePositionsMu.Lock()
+	current := make(map[string]parser.FileChange, len(a.filePositions))
+	for k, v := range a.filePositions {
+		current[k] = v
 	}
-	a.fileOffsetsMu.Unlock()
+	a.filePositionsMu.Unlock()
 
 	var changes []parser.FileChange
-	_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+	seen := make(map[string]struct{})
+	for _, dataDir := range a.dataDirs() {
+		source := parser.SourceName(dataDir)
+		_ = filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || filepath.Ext(path) != ".jsonl" {
+				return nil
+			}
+			if _, dup := seen[path]; dup {
+				return nil
+			}
+			seen[path] = struct{}{}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			last, known := current[path]
+			inode := parser.FileInode(info)
+			switch {
+			case !known:
+				changes = append(changes, parser.FileChange{Path: path, Inode: inode, Source: source})
+			case last.Inode != inode || info.Size() < last.Offset:
+				// Replaced or truncated: re-read from the start.
+				changes = append(changes, parser.FileChange{Path: path, Inode: inode, Source: source})
+			case info.Size() > last.Offset:
+				changes = append(changes, last)
+			}
 			return nil
+		})
+	}
+
+	if len(changes) == 0 {
+		return incrementalLoadedMsg{}
+	}
+
+	ctx := context.Background()
+	result, newOffsets := parser.ParseChanges(ctx, changes)
+	if a.Archive != nil {
+		_, _ = a.Archive.Append(result.Entries)
+	}
+	positions := make(map[string]parser.FileChange, len(newOffsets))
+	for _, fc := range changes {
+		if offset, ok := newOffsets[fc.Path]; ok {
+			positions[fc.Path] = parser.FileChange{Path: fc.Path, Offset: offset, Inode: fc.Inode, Source: fc.Source}
+		}
+	}
+	return incrementalLoadedMsg{entries: result.Entries, prompts: result.Prompts, positions: positions}
+}
+
+// dataDirs returns the Claude data directories to read, discovering the
+// standard locations when none were set.
+func (a App) dataDirs() []string {
+	if len(a.DataDirs) > 0 {
+		return a.DataDirs
+	}
+	return config.ClaudeDataDirs(nil, a.Config.General.DataDirs)
+}
+
+// blockOptions anchors blocks to every observed 5-hour window, including
+// the current one when the store is disabled, and records idle gaps.
+func (a App) blockOptions() domain.BlockOptions {
+	opts := domain.BlockOptions{Gaps: true}
+	if a.Windows != nil {
+		opts.Anchors = history.Starts(a.Windows.FiveHour())
+	}
+	if start, ok := windowStart(a.apiUsage); ok {
+		opts.Anchors = append(opts.Anchors, start)
+	}
+	return opts
+}
+
+// weeklyOptions places weekly windows by the observed weekly resets,
+// including the current one, or else by the configured reset time.
+func (a App) weeklyOptions() domain.WeeklyOptions {
+	opts := domain.WeeklyOptions{
+		ResetDay:  a.Config.Weekly.Weekday(),
+		ResetHour: a.Config.Weekly.Hour(),
+		Location:  a.tz,
+	}
+	if a.Windows != nil {
+		opts.Observed = a.Windows.SevenDay()
+	}
+	if a.apiUsage != nil {
+		if reset, err := a.apiUsage.SevenDay.ResetTime(); err == nil {
+			opts.Observed = append(opts.Observed, domain.ObservedWindow{
+				Start: domain.WeekStart(reset),
+				Peak:  a.apiUsage.SevenDay.Utilization,
+			})
+		}
+	}
+	return opts
+}
+
+// anomalyOptions converts the [anomalies] config.
+func anomalyOptions(c config.AnomaliesConfig) domain.AnomalyOptions {
+	opts := domain.AnomalyOptions{Method: domain.AnomalyMAD, K: c.K, Window: c.Window}
+	if c.Stddev() {
+		opts.Method = domain.AnomalyStdDev
+	}
+	return opts
+}
+
+// announceAnomalies notifies of the first anomaly that began within the
+// last day and was not announced before. Older anomalies are only marked.
+func (a *App) announceAnomalies(anomalies []domain.Anomaly) {
+	recent := time.Now().Add(-24 * time.Hour)
+	var fresh []domain.Anomaly
+	for _, an := range anomalies {
+		id := string(an.Kind) + "/" + an.Key
+		if a.announced[id] || an.Start.Before(recent) {
+			continue
 		}
-		info, err := d.Info()
+		a.announced[id] = true
+		fresh = append(fresh, an)
+	}
+	if len(fresh) == 0 || !a.Config.Notifications.Enabled {
+		return
+	}
+	an := fresh[0]
+	msg := i18n.Tf("anomaly_notice", i18n.T("anomaly_"+string(an.Kind)), an.Cost, an.Baseline)
+	if len(fresh) > 1 {
+		msg += " " + i18n.Tf("anomaly_more", len(fresh)-1)
+	}
+	a.notifications.SetMessage(msg)
+}
+
+// valueOptions compares usage with the configured plan.
+func (a App) valueOptions() domain.ValueOptions {
+	return domain.ValueOptions{
+		Plan:       a.Config.Plan.Label(),
+		Price:      a.Config.Plan.MonthlyPrice(),
+		BillingDay: a.Config.Plan.Day(),
+		Location:   a.tz,
+		Now:        time.Now(),
+	}
+}
+
+// budgetList converts the [budgets] config. Limits with an unknown period
+// are skipped here; "claude-smi budget status" reports them.
+func budgetList(c config.BudgetsConfig) []domain.Budget {
+	var budgets []domain.Budget
+	for _, l := range c.All() {
+		period, err := domain.ParseBudgetPeriod(l.Period)
 		if err != nil {
-			return nil
+			continue
 		}
-		lastOffset, known := currentOffsets[path]
-		if !known || info.Size() > lastOffset {
-			changes = append(changes, parser.FileChange{
-				Path:   path,
-				Offset: lastOffset,
-			})
+		budgets = append(budgets, domain.Budget{Period: period, Cost: l.Cost, Tokens: l.Tokens, Project: l.Project, Model: l.Model})
+	}
+	return budgets
+}
+
+// announceBudgets notifies when a budget crosses a threshold for the
+// first time in its period, naming the fullest budget that did.
+func (a *App) announceBudgets(statuses []domain.BudgetStatus) {
+	var crossed *domain.BudgetStatus
+	for i, s := range statuses {
+		id := s.Name() + "/" + s.Start.Format(time.RFC3339)
+		if s.Crossed <= a.budgetAlerts[id] {
+			continue
 		}
+		a.budgetAlerts[id] = s.Crossed
+		if crossed == nil || s.Share > crossed.Share {
+			crossed = &statuses[i]
+		}
+	}
+	if crossed == nil || !a.Config.Notifications.Enabled {
+		return
+	}
+	key := "budget_notice"
+	if crossed.Crossed >= 1 {
+		key = "budget_exceeded"
+	}
+	a.notifications.SetMessage(i18n.Tf(key, crossed.Name(), crossed.Share*100))
+}
+
+// sampleCap records the current 5-hour utilization along with the usage
+// logged in its window, and reports whether the sample was kept. Nothing
+// is sampled while the date range or the metadata filter could hide part
+// of the window.
+func (a App) sampleCap() bool {
+	start, ok := windowStart(a.apiUsage)
+	if !ok || a.Windows == nil || !a.MetaFilter.IsZero() {
+		return false
+	}
+	now := time.Now()
+	if a.dateRange.Since.After(start) || (!a.dateRange.Until.IsZero() && a.dateRange.Until.Before(now)) {
+		return false
+	}
+	return a.Windows.Sample(domain.NewCapSample(a.entries, start, now, a.apiUsage.FiveHour.Utilization))
+}
+
+// capEstimate returns the 5-hour cap estimated for the model mix of the
+// current window, nil until enough samples are recorded.
+func (a App) capEstimate() *domain.CapEstimate {
+	start, ok := windowStart(a.apiUsage)
+	if !ok || a.Windows == nil {
 		return nil
-	})
+	}
+	current := domain.NewCapSample(a.entries, start, time.Now(), a.apiUsage.FiveHour.Utilization)
+	c, ok := domain.CapFor(domain.EstimateCaps(a.Windows.Samples()), current.Mix)
+	if !ok {
+		return nil
+	}
+	return &c
+}
 
-	if len(changes) == 0 {
-		return incrementalLoadedMsg{}
+// windowStart returns the start of the current 5-hour window of usage.
+func windowStart(usage *api.UsageData) (time.Time, bool) {
+	if usage == nil {
+		return time.Time{}, false
+	}
+	reset, err := usage.FiveHour.ResetTime()
+	if err != nil {
+		return time.Time{}, false
 	}
+	return domain.WindowStart(reset), true
+}
 
-	ctx := context.Background()
-	entries, newOffsets := parser.ParseIncremental(ctx, changes)
-	return incrementalLoadedMsg{entries: entries, offsets: newOffsets}
+// saveWindows persists the observed API windows.
+func (a App) saveWindows() tea.Msg {
+	_ = a.Windows.Save()
+	return nil
 }
 
 func fetchApiUsage() tea.Msg {
@@ -104,51 +291,132 @@ func fetchPricing() tea.Msg {
 	return pricingMsg{table: table, err: err}
 }
 
+// promptsPerSession is the number of prompts the Prompts tab lists per
+// session.
+const promptsPerSession = 3
+
 func (a *App) processData(entries []domain.UsageEntry) {
 	entries = parser.Dedup(entries)
+	a.resolver.Apply(entries)
 	a.calc.ApplyAll(entries)
+	all := entries // before the range and metadata filters, for budgets and value
 
-	if timeFiltered, err := domain.FilterByTimeRange(entries, a.SinceFilter, a.UntilFilter, a.tz); err == nil {
-		entries = timeFiltered
+	// Relative bounds such as 24h move with every reprocess
+	rc := domain.RangeContext{Now: time.Now(), Location: a.tz, BillingDay: a.Config.Plan.Day()}
+	if r, err := rc.Parse(a.SinceFilter, a.UntilFilter); err == nil {
+		a.dateRange = r
+		entries = domain.FilterByRange(entries, r)
 	}
+	entries = domain.FilterByMetadata(entries, a.MetaFilter)
 
 	a.entries = entries
 
-	// Extract unique project paths
-	projectSet := make(map[string]struct{})
+	// Extract unique project roots and their display names
+	a.projectNames = make(map[string]string)
 	for _, e := range entries {
 		if e.ProjectPath != "" {
-			projectSet[e.ProjectPath] = struct{}{}
+			a.projectNames[e.ProjectPath] = e.ProjectName
 		}
 	}
-	a.projects = make([]string, 0, len(projectSet))
-	for p := range projectSet {
+	a.projects = make([]string, 0, len(a.projectNames))
+	for p := range a.projectNames {
 		a.projects = append(a.projects, p)
 	}
-	sort.Strings(a.projects)
+	sort.Slice(a.projects, func(i, j int) bool {
+		ni, nj := a.projectNames[a.projects[i]], a.projectNames[a.projects[j]]
+		if ni != nj {
+			return ni < nj
+		}
+		return a.projects[i] < a.projects[j]
+	})
+
+	// Extract data sources; a source that vanished clears the filter
+	sourceSet := make(map[string]struct{})
+	for _, e := range entries {
+		if e.Source != "" {
+			sourceSet[e.Source] = struct{}{}
+		}
+	}
+	a.sources = make([]string, 0, len(sourceSet))
+	for s := range sourceSet {
+		a.sources = append(a.sources, s)
+	}
+	sort.Strings(a.sources)
+	if _, ok := sourceSet[a.activeSource]; !ok {
+		a.activeSource = ""
+	}
 
-	// Apply project filter
+	// Apply project, source and expression filters. They are applied
+	// here rather than to a.entries so that changing them keeps the
+	// entries they drop.
 	filtered := entries
-	if len(a.activeProjects) > 0 {
+	if len(a.activeProjects) > 0 || a.activeSource != "" || a.filter != nil {
 		filtered = make([]domain.UsageEntry, 0)
 		for _, e := range entries {
-			if a.activeProjects[e.ProjectPath] {
-				filtered = append(filtered, e)
+			if len(a.activeProjects) > 0 && !a.activeProjects[e.ProjectPath] {
+				continue
+			}
+			if a.activeSource != "" && e.Source != a.activeSource {
+				continue
 			}
+			if !a.filter.Match(e) {
+				continue
+			}
+			filtered = append(filtered, e)
 		}
 	}
 	a.filteredEntries = filtered
 
-	a.blocks = domain.BuildBlocks(filtered)
+	a.blocks = domain.BuildBlocks(filtered, a.blockOptions())
 	a.daily = domain.AggregateDaily(filtered, a.tz)
+	weekly := a.weeklyOptions()
+	forecast := domain.BuildForecast(filtered, domain.ForecastOptions{Now: time.Now(), Location: a.tz, Weekly: &weekly})
+	sessions := domain.AggregateBySession(filtered)
+	anomalies := domain.DetectAnomalies(a.daily, a.blocks, sessions, a.tz, anomalyOptions(a.Config.Anomalies))
+	a.announceAnomalies(anomalies)
+	// Budgets count all spend, whatever the range and filters
+	budgets := domain.TrackBudgets(all, budgetList(a.Config.Budgets), domain.BudgetOptions{
+		Now:        time.Now(),
+		Location:   a.tz,
+		BillingDay: a.Config.Plan.Day(),
+	})
+	a.announceBudgets(budgets)
 
 	// Update views
 	a.liveView.SetData(filtered, a.blocks, a.daily)
 	if a.apiUsage != nil {
 		a.liveView.SetApiUsage(a.apiUsage)
 	}
+	a.liveView.SetForecast(forecast)
+	a.liveView.SetCap(a.capEstimate())
+	a.liveView.SetBudgets(budgets)
 	a.blocksView.SetData(a.blocks)
+	a.blocksView.SetAnomalies(anomalies)
 	a.dailyReportView.SetData(filtered)
+	a.dailyReportView.SetForecast(forecast)
+	a.dailyReportView.SetAnomalies(anomalies)
+	a.dailyReportView.SetBudgets(budgets)
+	a.dailyReportView.SetValue(domain.BuildValueReport(all, a.valueOptions()))
+	a.toolsView.SetData(filtered)
+	a.promptsView.SetData(domain.TopPromptsBySession(domain.AggregatePrompts(filtered, a.prompts), promptsPerSession))
+	a.weeklyView.SetData(domain.AggregateWeekly(filtered, weekly))
+	a.sessionsView.SetData(sessions, filtered)
+	a.cacheView.SetData(domain.BuildCacheReport(filtered, domain.CacheOptions{
+		Location: a.tz,
+		Rates:    a.calc.CacheRates,
+		Blocks:   a.blockOptions(),
+		Now:      time.Now(),
+	}))
+
+	// Projects are compared with each other, so the project filter does
+	// not apply.
+	projects := domain.NewProjectAccumulator(a.tz)
+	for _, e := range entries {
+		if (a.activeSource == "" || e.Source == a.activeSource) && a.filter.Match(e) {
+			projects.Add(e)
+		}
+	}
+	a.projectsView.SetData(projects.Projects(), a.activeProjects)
 
 	a.loading = false
 }
@@ -64,25 +64,28 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		)
 
 	case dataLoadedMsg:
-		// Store offsets from full scan
-		if msg.offsets != nil {
-			a.fileOffsetsMu.Lock()
-			a.fileOffsets = msg.offsets
-			a.fileOffsetsMu.Unlock()
+		// Store positions from full scan
+		if msg.positions != nil {
+			a.filePositionsMu.Lock()
+			a.filePositions = msg.positions
+			a.filePositionsMu.Unlock()
 		}
 		a.initialLoaded = true
+		a.prompts = msg.prompts
 		a.processData(msg.entries)
 		return a, nil
 
 	case incrementalLoadedMsg:
-		if len(msg.entries) > 0 {
-			// Update offsets
-			a.fileOffsetsMu.Lock()
-			for path, offset := range msg.offsets {
-				a.fileOffsets[path] = offset
-			}
-			a.fileOffsetsMu.Unlock()
+		// Positions advance even without new entries (e.g. only non-usage
+		// records were appended), so the same bytes are not re-read.
+		a.filePositionsMu.Lock()
+		for path, pos := range msg.positions {
+			a.filePositions[path] = pos
+		}
+		a.filePositionsMu.Unlock()
 
+		a.prompts = append(a.prompts, msg.prompts...)
+		if len(msg.entries) > 0 {
 			// Merge new entries with existing and reprocess
 			merged := make([]domain.UsageEntry, 0, len(a.entries)+len(msg.entries))
 			merged = append(merged, a.entries...)
@@ -95,8 +98,22 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			a.notifications.SetMessage("API: " + msg.err.Error())
 		} else if msg.data != nil {
+			prev, _ := windowStart(a.apiUsage)
 			a.apiUsage = msg.data
+			var cmd tea.Cmd
+			observed := a.Windows != nil && a.Windows.Observe(msg.data)
+			if a.sampleCap() || observed {
+				cmd = a.saveWindows
+			}
+			// A new window re-anchors the blocks; a new peak shows in
+			// the weekly windows.
+			cur, _ := windowStart(msg.data)
+			if (observed || !cur.Equal(prev)) && len(a.entries) > 0 {
+				a.processData(a.entries)
+			}
 			a.liveView.SetApiUsage(msg.data)
+			a.liveView.SetCap(a.capEstimate())
+			return a, cmd
 		}
 		return a, nil
 
@@ -121,9 +138,19 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if err == nil {
 			a.tz = newTz
 		}
+		// Times in the filter are read in the timezone
+		if f, err := domain.ParseFilterExpr(a.filter.String(), a.tz); err == nil {
+			a.filter = f
+		}
 		a.liveView = views.NewLiveView(a.tz, a.calc)
 		a.blocksView = views.NewBlocksView(a.tz)
 		a.dailyReportView = views.NewDailyReportView(a.tz)
+		a.toolsView = views.NewToolsView(a.tz)
+		a.promptsView = views.NewPromptsView(a.tz)
+		a.weeklyView = views.NewWeeklyView(a.tz)
+		a.sessionsView = views.NewSessionsView(a.tz)
+		a.projectsView = views.NewProjectsView(a.tz)
+		a.cacheView = views.NewCacheView(a.tz)
 		a.processData(a.entries)
 		return a, nil
 	}
@@ -135,6 +162,9 @@ func (a App) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if a.projectPicking {
 		return a.handleProjectPicker(msg)
 	}
+	if a.filterEditing {
+		return a.handleFilterPrompt(msg)
+	}
 
 	var cmd tea.Cmd
 	switch a.activeView {
@@ -148,6 +178,27 @@ func (a App) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case ViewDailyReport:
 		cmd = a.dailyReportView.Update(msg)
+	case ViewTools:
+		cmd = a.toolsView.Update(msg)
+	case ViewPrompts:
+		cmd = a.promptsView.Update(msg)
+	case ViewWeekly:
+		cmd = a.weeklyView.Update(msg)
+	case ViewSessions:
+		cmd = a.sessionsView.Update(msg)
+		if a.sessionsView.ScrollReset {
+			a.scroll.viewScrollY[a.activeView] = 0
+			a.sessionsView.ScrollReset = false
+		}
+	case ViewProjects:
+		cmd = a.projectsView.Update(msg)
+		if root := a.projectsView.Picked; root != "" {
+			a.projectsView.Picked = ""
+			a.filterToProject(root)
+			a.processData(a.entries)
+		}
+	case ViewCache:
+		cmd = a.cacheView.Update(msg)
 	}
 	if cmd != nil {
 		return a, cmd
@@ -167,6 +218,18 @@ func (a App) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.activeView = ViewBlocks
 	case "3":
 		a.activeView = ViewDailyReport
+	case "4":
+		a.activeView = ViewTools
+	case "5":
+		a.activeView = ViewPrompts
+	case "6":
+		a.activeView = ViewWeekly
+	case "7":
+		a.activeView = ViewSessions
+	case "8":
+		a.activeView = ViewProjects
+	case "9":
+		a.activeView = ViewCache
 	case "tab":
 		a.activeView = (a.activeView + 1) % ViewCount
 	case "shift+tab":
@@ -185,10 +248,60 @@ func (a App) handleGlobalKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.projectPicking = true
 			a.projectCursor = 0
 		}
+	case "S":
+		if len(a.sources) > 1 {
+			a.cycleSource()
+			a.processData(a.entries)
+		}
+	case "/":
+		a.filterEditing = true
+		a.filterInput = a.filter.String()
+		a.filterErr = ""
 	}
 	return a, nil
 }
 
+// handleFilterPrompt edits the filter expression. Enter applies it, or
+// clears the filter when the input is empty; Esc leaves it unchanged.
+func (a App) handleFilterPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		a.filterEditing = false
+	case tea.KeyEnter:
+		if err := a.SetFilter(a.filterInput); err != nil {
+			a.filterErr = err.Error()
+			return a, nil
+		}
+		a.filterEditing = false
+		a.processData(a.entries)
+	case tea.KeyBackspace:
+		if r := []rune(a.filterInput); len(r) > 0 {
+			a.filterInput = string(r[:len(r)-1])
+		}
+		a.filterErr = ""
+	case tea.KeyCtrlU:
+		a.filterInput = ""
+		a.filterErr = ""
+	case tea.KeySpace:
+		a.filterInput += " "
+	case tea.KeyRunes:
+		a.filterInput += string(msg.Runes)
+		a.filterErr = ""
+	}
+	return a, nil
+}
+
+// SetFilter compiles expr, interpreting times in the app timezone, and
+// filters every view by it. An empty expr clears the filter.
+func (a *App) SetFilter(expr string) error {
+	f, err := domain.ParseFilterExpr(expr, a.tz)
+	if err != nil {
+		return err
+	}
+	a.filter = f
+	return nil
+}
+
 func (a App) handleProjectPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	totalOptions := len(a.projects) + 1
 
@@ -222,6 +335,32 @@ func (a App) handleProjectPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// filterToProject filters by root alone, or clears the project filter if
+// it already does.
+func (a *App) filterToProject(root string) {
+	if len(a.activeProjects) == 1 && a.activeProjects[root] {
+		a.activeProjects = make(map[string]bool)
+		return
+	}
+	a.activeProjects = map[string]bool{root: true}
+}
+
+// cycleSource advances the source filter: all, then each source in turn.
+func (a *App) cycleSource() {
+	next := 0 // from all sources to the first
+	for i, s := range a.sources {
+		if s == a.activeSource {
+			next = i + 1
+			break
+		}
+	}
+	if next >= len(a.sources) {
+		a.activeSource = ""
+		return
+	}
+	a.activeSource = a.sources[next]
+}
+
 func (a App) updateOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch a.overlay {
 	case OverlayHelp:
@@ -297,6 +436,12 @@ func (a *App) propagateAnimTick() {
 	a.liveView.AnimTick = a.animTick
 	a.blocksView.AnimTick = a.animTick
 	a.dailyReportView.AnimTick = a.animTick
+	a.toolsView.AnimTick = a.animTick
+	a.promptsView.AnimTick = a.animTick
+	a.weeklyView.AnimTick = a.animTick
+	a.sessionsView.AnimTick = a.animTick
+	a.projectsView.AnimTick = a.animTick
+	a.cacheView.AnimTick = a.animTick
 	a.helpOverlay.AnimTick = a.animTick
 	if a.settingsOverlay != nil {
 		a.settingsOverlay.SetAnimTick(a.animTick)
@@ -2,8 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/anomredux/claude-smi/internal/i18n"
@@ -60,7 +62,7 @@ func (a App) View() string {
 		Render(content)
 
 	banner := a.notifications.RenderBanner(a.width)
-	if banner != "" {
+	if banner != "" && !a.filterEditing {
 		return tabBar + "\n" + content + "\n" + banner
 	}
 
@@ -68,12 +70,12 @@ func (a App) View() string {
 }
 
 func (a App) renderTabs() string {
-	viewNames := []string{i18n.T("tab_live"), i18n.T("tab_blocks"), i18n.T("tab_daily_report")}
+	viewNames := []string{i18n.T("tab_live"), i18n.T("tab_blocks"), i18n.T("tab_daily_report"), i18n.T("tab_tools"), i18n.T("tab_prompts"), i18n.T("tab_weekly"), i18n.T("tab_sessions"), i18n.T("tab_projects"), i18n.T("tab_cache")}
 
 	var projectDisplay string
 	if len(a.activeProjects) == 1 {
 		for p := range a.activeProjects {
-			projectDisplay = p
+			projectDisplay = a.projectName(p)
 		}
 	} else if len(a.activeProjects) > 1 {
 		projectDisplay = fmt.Sprintf("%d projects", len(a.activeProjects))
@@ -84,6 +86,9 @@ func (a App) renderTabs() string {
 		ActiveIndex:   int(a.activeView),
 		Width:         a.width,
 		ActiveProject: projectDisplay,
+		ActiveSource:  a.activeSource,
+		ActiveFilter:  a.filter.String(),
+		ActiveRange:   a.rangeLabel(),
 	}.Render()
 }
 
@@ -103,11 +108,30 @@ func (a App) renderActiveView(contentHeight int, compact bool) string {
 		return a.blocksView.Render(a.width, contentHeight, compact)
 	case ViewDailyReport:
 		return a.dailyReportView.Render(a.width, renderHeight, compact)
+	case ViewTools:
+		return a.toolsView.Render(a.width, renderHeight, compact)
+	case ViewPrompts:
+		return a.promptsView.Render(a.width, renderHeight, compact)
+	case ViewWeekly:
+		return a.weeklyView.Render(a.width, renderHeight, compact)
+	case ViewSessions:
+		if a.sessionsView.InDetail() {
+			return a.sessionsView.Render(a.width, renderHeight, compact)
+		}
+		// List mode: use actual height for internal list scrolling
+		return a.sessionsView.Render(a.width, contentHeight, compact)
+	case ViewProjects:
+		return a.projectsView.Render(a.width, contentHeight, compact)
+	case ViewCache:
+		return a.cacheView.Render(a.width, contentHeight, compact)
 	}
 	return ""
 }
 
 func (a App) renderStatusBar(contentHeight int) string {
+	if a.filterEditing {
+		return a.renderFilterPrompt()
+	}
 	var scrollInfo string
 	if a.scroll.lastContentLines > contentHeight {
 		offset := a.scroll.viewScrollY[a.activeView]
@@ -124,6 +148,26 @@ func (a App) renderStatusBar(contentHeight int) string {
 	return components.StatusBar{Width: a.width, ScrollInfo: scrollInfo}.Render()
 }
 
+// renderFilterPrompt replaces the status bar while the filter expression
+// is edited, showing why the input does not compile if it does not.
+func (a App) renderFilterPrompt() string {
+	sep := theme.MutedStyle.Render(strings.Repeat("─", a.width))
+	label := lipgloss.NewStyle().Foreground(theme.ColorGold).Bold(true).Render("  / " + i18n.T("filter") + ": ")
+	cursor := lipgloss.NewStyle().Foreground(theme.ColorGold).Render("█")
+	line := label + theme.BodyStyle.Render(a.filterInput) + cursor
+	hint := theme.MutedStyle.Render(i18n.T("filter_prompt_help"))
+	if a.filterErr != "" {
+		hint = lipgloss.NewStyle().Foreground(theme.ColorPeach).Render(a.filterErr)
+	}
+	if gap := a.width - lipgloss.Width(line) - lipgloss.Width(hint) - 2; gap >= 2 {
+		line += strings.Repeat(" ", gap) + hint
+	} else if a.filterErr != "" {
+		line = label + lipgloss.NewStyle().Foreground(theme.ColorPeach).Render(
+			components.TruncateText(a.filterErr, a.width-lipgloss.Width(label)-2))
+	}
+	return sep + "\n" + line
+}
+
 func (a *App) renderProjectPicker() string {
 	innerW := a.width - 4
 
@@ -174,9 +218,6 @@ func (a *App) renderProjectPicker() string {
 			lines = append(lines, fmt.Sprintf("  %s%s %s", arrow, check, style.Render(label)))
 		} else {
 			p := a.projects[displayIdx-1]
-			projectName := filepath.Base(p)
-			parentDir := filepath.Base(filepath.Dir(p))
-			label := parentDir + "/" + projectName
 			check := checkOff
 			if a.activeProjects[p] {
 				check = checkOn
@@ -185,7 +226,8 @@ func (a *App) renderProjectPicker() string {
 			if isCursor {
 				style = cursorStyle
 			}
-			lines = append(lines, fmt.Sprintf("  %s%s %s", arrow, check, style.Render(label)))
+			lines = append(lines, fmt.Sprintf("  %s%s %s  %s", arrow, check,
+				style.Render(a.projectName(p)), theme.MutedStyle.Render(shortenHome(p))))
 		}
 	}
 
@@ -238,3 +280,49 @@ func (a App) renderOverlay() string {
 	}
 	return theme.CardStyle.Width(60).Height(20).Render("Overlay")
 }
+
+// rangeLabel renders the resolved date range for the tab bar, with times
+// only on bounds that do not fall on a day boundary; "" when unbounded.
+func (a App) rangeLabel() string {
+	r := a.dateRange
+	if r.Since.IsZero() && r.Until.IsZero() {
+		return ""
+	}
+	format := func(t time.Time, end bool) string {
+		if t.IsZero() {
+			return "…"
+		}
+		t = t.In(a.tz)
+		if end {
+			t = t.Add(time.Nanosecond)
+		}
+		if t.Hour() == 0 && t.Minute() == 0 {
+			if end {
+				t = t.AddDate(0, 0, -1)
+			}
+			return t.Format("2006-01-02")
+		}
+		return t.Format("2006-01-02 15:04")
+	}
+	return format(r.Since, false) + " – " + format(r.Until, true)
+}
+
+// projectName returns the display name of a project root.
+func (a App) projectName(root string) string {
+	if name := a.projectNames[root]; name != "" {
+		return name
+	}
+	return filepath.Base(root)
+}
+
+// shortenHome abbreviates the home directory prefix of path to "~".
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home); ok && (rest == "" || os.IsPathSeparator(rest[0])) {
+		return "~" + rest
+	}
+	return path
+}
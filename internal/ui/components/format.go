@@ -3,6 +3,8 @@ package components
 import (
 	"fmt"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // FormatNumber formats an integer with comma separators (e.g. 1,234,567).
@@ -52,3 +54,23 @@ func FormatDuration(d time.Duration) string {
 	}
 	return fmt.Sprintf("%dm", m)
 }
+
+// TruncateText shortens plain text to at most width terminal cells,
+// ending in "…" when cut.
+func TruncateText(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	w := 0
+	for i, r := range s {
+		rw := lipgloss.Width(string(r))
+		if w+rw > width-1 {
+			return s[:i] + "…"
+		}
+		w += rw
+	}
+	return s
+}
@@ -46,3 +46,22 @@ func TestFormatDuration(t *testing.T) {
 		}
 	}
 }
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly10!", 10, "exactly10!"},
+		{"a longer prompt", 8, "a longe…"},
+		{"한글 프롬프트", 6, "한글 …"},
+	}
+	for _, tt := range tests {
+		got := TruncateText(tt.input, tt.width)
+		if got != tt.want {
+			t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
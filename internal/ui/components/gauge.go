@@ -18,6 +18,7 @@ type SemicircleGauge struct {
 	Label   string  // e.g. "5h Session"
 	Percent float64 // 0.0 ~ N (can exceed 1.0)
 	Width   int     // character width of the gauge area
+	Note    string  // optional muted line under the percentage
 }
 
 // Render returns the gauge as a block of lines.
@@ -70,6 +71,9 @@ func (g SemicircleGauge) Render() []string {
 	block = append(block, "")
 	block = append(block, arcLines...)
 	block = append(block, CenterText(styledPct, w))
+	if g.Note != "" {
+		block = append(block, CenterText(theme.MutedStyle.Render(g.Note), w))
+	}
 
 	return block
 }
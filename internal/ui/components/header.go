@@ -2,7 +2,6 @@ package components
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -15,6 +14,9 @@ type TabBar struct {
 	ActiveIndex   int
 	Width         int
 	ActiveProject string
+	ActiveSource  string
+	ActiveFilter  string // filter expression; truncated to fit
+	ActiveRange   string // resolved date range
 }
 
 // Package-level cached styles for tab bar rendering.
@@ -30,24 +32,31 @@ var (
 				Padding(0, 1)
 )
 
-// Render returns the styled tab bar with bottom separator line.
+// Render returns the styled tab bar with bottom separator line. When the
+// tabs do not fit, inactive tabs show only their number.
 func (tb TabBar) Render() string {
-	var tabs []string
-	for i, name := range tb.ViewNames {
-		label := fmt.Sprintf("%d %s", i+1, name)
-		if i == tb.ActiveIndex {
-			tabs = append(tabs, tabActiveStyle.Render(label))
-		} else {
-			tabs = append(tabs, tabInactiveStyle.Render(label))
-		}
+	line := tb.renderTabs(false)
+	if lipgloss.Width(line) > tb.Width-2 {
+		line = tb.renderTabs(true)
 	}
 
-	line := strings.Join(tabs, "")
-
 	if tb.ActiveProject != "" {
-		projectName := filepath.Base(tb.ActiveProject)
 		line += "  " +
-			lipgloss.NewStyle().Foreground(theme.ColorMauve).Render("["+projectName+"]")
+			lipgloss.NewStyle().Foreground(theme.ColorMauve).Render("["+tb.ActiveProject+"]")
+	}
+	if tb.ActiveSource != "" {
+		line += "  " +
+			lipgloss.NewStyle().Foreground(theme.ColorSkyBlue).Render("<"+tb.ActiveSource+">")
+	}
+	if tb.ActiveRange != "" {
+		line += "  " +
+			lipgloss.NewStyle().Foreground(theme.ColorLavender).Render(tb.ActiveRange)
+	}
+	if tb.ActiveFilter != "" {
+		if room := tb.Width - lipgloss.Width(line) - 8; room >= 8 {
+			line += "  " +
+				lipgloss.NewStyle().Foreground(theme.ColorPeach).Render("{"+TruncateText(tb.ActiveFilter, room)+"}")
+		}
 	}
 
 	tabLine := lipgloss.NewStyle().
@@ -59,3 +68,18 @@ func (tb TabBar) Render() string {
 
 	return tabLine + "\n" + sep
 }
+
+func (tb TabBar) renderTabs(short bool) string {
+	var tabs []string
+	for i, name := range tb.ViewNames {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if i == tb.ActiveIndex {
+			tabs = append(tabs, tabActiveStyle.Render(label))
+		} else if short {
+			tabs = append(tabs, tabInactiveStyle.Render(fmt.Sprintf("%d", i+1)))
+		} else {
+			tabs = append(tabs, tabInactiveStyle.Render(label))
+		}
+	}
+	return strings.Join(tabs, "")
+}
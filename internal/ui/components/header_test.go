@@ -0,0 +1,22 @@
+package components
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTabBar_ShortensWhenNarrow(t *testing.T) {
+	tb := TabBar{ViewNames: []string{"Live Dashboard", "Session Blocks", "Report"}, ActiveIndex: 1, Width: 120}
+	if out := tb.Render(); !strings.Contains(out, "1 Live Dashboard") {
+		t.Errorf("wide tab bar lacks full labels:\n%s", out)
+	}
+
+	tb.Width = 30
+	out := tb.Render()
+	if strings.Contains(out, "Live Dashboard") || strings.Contains(out, "Report") {
+		t.Errorf("narrow tab bar keeps inactive labels:\n%s", out)
+	}
+	if !strings.Contains(out, "2 Session Blocks") {
+		t.Errorf("narrow tab bar lacks the active label:\n%s", out)
+	}
+}
@@ -0,0 +1,44 @@
+package components
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/theme"
+)
+
+// heatFillOrder lists the braille dots of a character bottom-up, so a
+// cell fills like a bar.
+var heatFillOrder = [8]int{
+	brailleDots[3][0], brailleDots[3][1],
+	brailleDots[2][0], brailleDots[2][1],
+	brailleDots[1][0], brailleDots[1][1],
+	brailleDots[0][0], brailleDots[0][1],
+}
+
+// HeatCell renders a heatmap cell of width characters for an intensity t
+// in [0, 1]: braille dots fill bottom-up and take their color from the
+// progress gradient. Zero renders a single dim dot per character.
+func HeatCell(t float64, width int) string {
+	if t <= 0 {
+		return theme.MutedStyle.Render(strings.Repeat(string(rune(0x2800|brailleDots[3][0])), width))
+	}
+	t = math.Min(t, 1)
+	n := int(math.Ceil(t * 8))
+	code := 0x2800
+	for _, dot := range heatFillOrder[:n] {
+		code |= dot
+	}
+	hex := theme.MultiStopGradient(t, theme.ProgressGradient)
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(hex)).Render(strings.Repeat(string(rune(code)), width))
+}
+
+// HeatLegend renders width cells from low to high intensity.
+func HeatLegend(width int) string {
+	var sb strings.Builder
+	for i := 0; i < width; i++ {
+		sb.WriteString(HeatCell(float64(i+1)/float64(width), 1))
+	}
+	return sb.String()
+}
@@ -0,0 +1,25 @@
+package components
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/theme"
+)
+
+// ProgressBar renders a bar of width characters filled to share. The fill
+// takes its color from the progress gradient; a share of 1 or more turns
+// the whole bar red.
+func ProgressBar(share float64, width int) string {
+	if width < 1 {
+		return ""
+	}
+	filled := int(math.Round(math.Min(math.Max(share, 0), 1) * float64(width)))
+	color := lipgloss.Color(theme.MultiStopGradient(math.Min(share, 1), theme.ProgressGradient))
+	if share >= 1 {
+		color = theme.ColorWeekendRed
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(theme.ColorBorder).Render(strings.Repeat("░", width-filled))
+}
@@ -35,6 +35,7 @@ func (s StatusBar) renderKeyHints() string {
 		{"?", i18n.T("status_help")},
 		{"s", i18n.T("status_settings")},
 		{"p", i18n.T("status_project")},
+		{"/", i18n.T("status_filter")},
 		{"r", i18n.T("status_refresh")},
 		{"q", i18n.T("status_quit")},
 	}
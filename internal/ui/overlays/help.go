@@ -24,7 +24,7 @@ func (h *HelpOverlay) Render(width, height int) string {
 		key  string
 		desc string
 	}{
-		{"1 / 2 / 3", i18n.T("help_switch_views")},
+		{"1 - 9", i18n.T("help_switch_views")},
 		{"Tab / Shift+Tab", i18n.T("help_cycle_views")},
 		{"", ""},
 		{"j / k / Down / Up", i18n.T("help_navigate")},
@@ -35,8 +35,12 @@ func (h *HelpOverlay) Render(width, height int) string {
 		{"s", i18n.T("help_open_settings")},
 		{"r", i18n.T("help_force_refresh")},
 		{"p", i18n.T("help_project_filter")},
+		{"S", i18n.T("help_source_filter")},
+		{"/", i18n.T("help_filter")},
 		{"", ""},
 		{"h / l / Left / Right", i18n.T("help_navigate_months")},
+		{"v", i18n.T("help_tool_scope")},
+		{"o / O", i18n.T("help_sort")},
 		{"", ""},
 		{"PgUp / PgDn", i18n.T("help_page_scroll")},
 		{"g / G", i18n.T("help_top_bottom")},
@@ -16,6 +16,7 @@ import (
 
 type BlocksView struct {
 	blocks      []domain.SessionBlock
+	anomalies   map[string]domain.Anomaly // by block key
 	tz          *time.Location
 	cursor      int
 	detail      bool
@@ -35,6 +36,11 @@ func (v *BlocksView) SetData(blocks []domain.SessionBlock) {
 	}
 }
 
+// SetAnomalies marks the blocks among anomalies as outliers.
+func (v *BlocksView) SetAnomalies(anomalies []domain.Anomaly) {
+	v.anomalies = domain.AnomalyKeys(anomalies, domain.AnomalyBlock)
+}
+
 func (v *BlocksView) Update(msg tea.Msg) tea.Cmd {
 	if km, ok := msg.(tea.KeyMsg); ok {
 		if v.detail {
@@ -62,7 +68,7 @@ func (v *BlocksView) Update(msg tea.Msg) tea.Cmd {
 			}
 			return KeyHandledCmd
 		case "enter":
-			if len(v.blocks) > 0 {
+			if b := v.selected(); b != nil && !b.IsGap {
 				v.detail = true
 				v.ScrollReset = true
 			}
@@ -72,6 +78,15 @@ func (v *BlocksView) Update(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// selected returns the block under the cursor, nil if there is none.
+func (v *BlocksView) selected() *domain.SessionBlock {
+	i := len(v.blocks) - 1 - v.cursor
+	if i < 0 || i >= len(v.blocks) {
+		return nil
+	}
+	return &v.blocks[i]
+}
+
 // InDetail returns whether the view is in detail mode.
 func (v *BlocksView) InDetail() bool {
 	return v.detail
@@ -220,14 +235,38 @@ func (v *BlocksView) renderList(cardWidth, contentHeight int, compact bool) stri
 		startStr := b.StartTime.In(v.tz).Format("Jan 02 15:04")
 		endDisplay := b.EndTime.Add(-1 * time.Minute)
 		endStr := endDisplay.In(v.tz).Format("Jan 02 15:04")
+		endColor := theme.ColorBodyText
+		if b.CutShort {
+			endStr += " ✂"
+			endColor = theme.ColorPeach
+		}
 
 		startCell := cellStyle(cols[1].width, cols[1].align, hl).
 			Foreground(theme.ColorBodyText).
 			Render(startStr)
 		endCell := cellStyle(cols[2].width, cols[2].align, hl).
-			Foreground(theme.ColorBodyText).
+			Foreground(endColor).
 			Render(endStr)
 
+		// Gap between cells also needs highlight background
+		gap := " "
+		if hl {
+			gap = lipgloss.NewStyle().Background(theme.ColorElevatedBg).Render(" ")
+		}
+
+		// Idle gap — one muted cell spanning the usage columns
+		if b.IsGap {
+			spanW := cols[3].width + cols[4].width + cols[5].width + cols[6].width + cols[7].width + 4
+			idleCell := cellStyle(spanW, lipgloss.Center, hl).
+				Foreground(theme.ColorMutedText).
+				Render(i18n.Tf("idle_gap", components.FormatDuration(b.EndTime.Sub(b.StartTime))))
+			statusCell := cellStyle(cols[8].width, cols[8].align, hl).
+				Foreground(theme.ColorMutedText).
+				Render(string(b.Status))
+			rows = append(rows, strings.Join([]string{numCell, startCell, endCell, idleCell, statusCell}, gap))
+			continue
+		}
+
 		// Token columns with color gradient (same as report stat cards)
 		inputCell := cellStyle(cols[3].width, cols[3].align, hl).
 			Foreground(theme.ColorLavender).
@@ -242,10 +281,16 @@ func (v *BlocksView) renderList(cardWidth, contentHeight int, compact bool) stri
 			Foreground(theme.ColorGold).
 			Render(components.FormatCompact(b.CacheCreationTokens))
 
-		// Cost
+		// Cost — outliers are flagged in red
+		costStr := fmt.Sprintf("$%.2f", b.TotalCost)
+		costColor := theme.ColorSkyBlue
+		if _, ok := v.anomalies[domain.BlockKey(b)]; ok {
+			costStr = "▲ " + costStr
+			costColor = theme.ColorWeekendRed
+		}
 		costCell := cellStyle(cols[7].width, cols[7].align, hl).
-			Foreground(theme.ColorSkyBlue).
-			Render(fmt.Sprintf("$%.2f", b.TotalCost))
+			Foreground(costColor).
+			Render(costStr)
 
 		// Status — animated for active
 		var statusCell string
@@ -264,12 +309,6 @@ func (v *BlocksView) renderList(cardWidth, contentHeight int, compact bool) stri
 				Render(string(b.Status))
 		}
 
-		// Gap between cells also needs highlight background
-		gap := " "
-		if hl {
-			gap = lipgloss.NewStyle().Background(theme.ColorElevatedBg).Render(" ")
-		}
-
 		row := strings.Join([]string{
 			numCell, startCell, endCell,
 			inputCell, outputCell, cwCell, crCell,
@@ -324,6 +363,20 @@ func (v *BlocksView) renderDetail(cardWidth int, compact bool) string {
 		{Value: components.FormatCompact(b.CacheCreationTokens), Label: i18n.T("cache_create"), Width: statW, Color: theme.ColorGold},
 	}
 	summaryCard.Content = components.CenterBlock(components.RenderStatRow(stats, statGap), innerW)
+	var notes []string
+	if b.Anchored {
+		notes = append(notes, i18n.T("block_anchored"))
+	}
+	if b.CutShort {
+		notes = append(notes, i18n.Tf("block_cut_short", b.EndTime.In(v.tz).Format("15:04")))
+	}
+	if an, ok := v.anomalies[domain.BlockKey(b)]; ok {
+		notes = append(notes, lipgloss.NewStyle().Foreground(theme.ColorWeekendRed).Render(
+			i18n.Tf("anomaly_note", an.Baseline, an.Score)))
+	}
+	if len(notes) > 0 {
+		summaryCard.Content += "\n" + components.CenterBlock(theme.MutedStyle.Render(strings.Join(notes, " · ")), innerW)
+	}
 
 	// Model breakdown card — table layout
 	modelCard := components.Card{
@@ -389,6 +442,14 @@ func (v *BlocksView) renderDetail(cardWidth int, compact bool) string {
 	}
 	modelCard.Content = strings.Join(modelRows, "\n")
 
+	// Tool calls card
+	toolsCard := components.Card{
+		Title:   theme.GradientText(i18n.T("tool_usage"), string(theme.ColorSkyBlue), string(theme.ColorMauve)),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	toolsCard.Content = renderToolTable(domain.SortedTools(b.Tools), toolsCard.InnerWidth(), 10)
+
 	// Recent entries card
 	entriesCard := components.Card{
 		Title:   theme.GradientText(i18n.T("recent_entries"), string(theme.ColorMauve), string(theme.ColorPeach)),
@@ -415,5 +476,5 @@ func (v *BlocksView) renderDetail(cardWidth int, compact bool) string {
 
 	footer := components.HelpFooter(i18n.T("detail_back_help"))
 
-	return summaryCard.Render() + "\n" + modelCard.Render() + "\n" + entriesCard.Render() + "\n" + footer
+	return summaryCard.Render() + "\n" + modelCard.Render() + "\n" + toolsCard.Render() + "\n" + entriesCard.Render() + "\n" + footer
 }
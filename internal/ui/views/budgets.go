@@ -0,0 +1,43 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// budgetBars renders one line per budget: its name, a progress bar, the
+// amounts against the limits and the share used. The Live and Report
+// views share it.
+func budgetBars(statuses []domain.BudgetStatus, width int) string {
+	const nameW, amountW, pctW = 20, 30, 6
+	barW := max(width-nameW-amountW-pctW-3, 10)
+
+	var lines []string
+	for _, s := range statuses {
+		var amounts []string
+		if s.Cost > 0 {
+			amounts = append(amounts, fmt.Sprintf("$%.2f / $%.2f", s.Spent, s.Cost))
+		}
+		if s.Tokens > 0 {
+			amounts = append(amounts, fmt.Sprintf("%s / %s", components.FormatCompact(s.Used), components.FormatCompact(s.Tokens)))
+		}
+		pctColor := theme.ColorBodyText
+		if s.Crossed >= 1 {
+			pctColor = theme.ColorWeekendRed
+		} else if s.Crossed > 0 {
+			pctColor = theme.ColorPeach
+		}
+		lines = append(lines, strings.Join([]string{
+			lipgloss.NewStyle().Width(nameW).Foreground(theme.ColorBrightText).Render(components.TruncateText(s.Name(), nameW)),
+			components.ProgressBar(s.Share, barW),
+			lipgloss.NewStyle().Width(amountW).Align(lipgloss.Right).Foreground(theme.ColorSkyBlue).Render(strings.Join(amounts, " · ")),
+			lipgloss.NewStyle().Width(pctW).Align(lipgloss.Right).Foreground(pctColor).Bold(true).Render(fmt.Sprintf("%.0f%%", s.Share*100)),
+		}, " "))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,256 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// CacheGroup selects the rows of the cache view.
+type CacheGroup int
+
+const (
+	CacheBySession CacheGroup = iota
+	CacheByBlock
+	CacheByProject
+	CacheByDay
+	cacheGroupCount
+)
+
+// CacheView shows how well prompt caching pays off: hit rate, read/write
+// ratio, net savings and wasted cache writes, in total and per group.
+type CacheView struct {
+	report   domain.CacheReport
+	tz       *time.Location
+	group    CacheGroup
+	cursor   int
+	scroll   int
+	AnimTick uint
+}
+
+func NewCacheView(tz *time.Location) *CacheView {
+	return &CacheView{tz: tz}
+}
+
+func (v *CacheView) SetData(r domain.CacheReport) {
+	v.report = r
+	if n := len(v.rows()); v.cursor >= n {
+		v.cursor = max(0, n-1)
+	}
+}
+
+// rows returns the stats of the selected group.
+func (v *CacheView) rows() []domain.CacheStats {
+	switch v.group {
+	case CacheByBlock:
+		return v.report.Blocks
+	case CacheByProject:
+		return v.report.Projects
+	case CacheByDay:
+		return v.report.Days
+	}
+	return v.report.Sessions
+}
+
+// groupName names the selected group.
+func (v *CacheView) groupName() string {
+	switch v.group {
+	case CacheByBlock:
+		return i18n.T("session_blocks")
+	case CacheByProject:
+		return i18n.T("projects")
+	case CacheByDay:
+		return i18n.T("cache_days")
+	}
+	return i18n.T("sessions")
+}
+
+func (v *CacheView) Update(msg tea.Msg) tea.Cmd {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch km.String() {
+	case "j", "down":
+		if v.cursor < len(v.rows())-1 {
+			v.cursor++
+		}
+		return KeyHandledCmd
+	case "k", "up":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+		return KeyHandledCmd
+	case "v":
+		v.group = (v.group + 1) % cacheGroupCount
+		v.cursor, v.scroll = 0, 0
+		return KeyHandledCmd
+	}
+	return nil
+}
+
+// label names a row of the selected group.
+func (v *CacheView) label(s domain.CacheStats) string {
+	switch v.group {
+	case CacheBySession:
+		id := s.Key
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		if s.Project == "" {
+			return id
+		}
+		return s.Project + " · " + id
+	case CacheByBlock:
+		return s.Start.In(v.tz).Format("Jan 02 15:04")
+	case CacheByDay:
+		return s.Start.Format("Mon Jan 02")
+	}
+	if s.Key == "" {
+		return "-"
+	}
+	return s.Key
+}
+
+func (v *CacheView) Render(width, height int, compact bool) string {
+	rows := v.rows()
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(fmt.Sprintf("%s · %s (%d)", i18n.T("cache_efficiency"), v.groupName(), len(rows)), v.AnimTick),
+		Width:   width - 4,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	var lines []string
+	lines = append(lines, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("cache_help"))))
+
+	total := v.report.Total
+	netColor := theme.ColorSkyBlue
+	if total.Net < 0 {
+		netColor = theme.ColorWeekendRed
+	}
+	statGap := 2
+	statW := max((innerW-statGap*3)/4, 10)
+	stats := []components.StatCard{
+		{Value: fmt.Sprintf("%.0f%%", total.HitRate*100), Sub: i18n.Tf("cache_read_tokens", components.FormatCompact(total.CacheReadTokens)), Label: i18n.T("cache_hit_rate"), Width: statW, Color: theme.ColorLavender},
+		{Value: fmt.Sprintf("%.1f×", total.ReadRatio), Sub: i18n.Tf("cache_written_tokens", components.FormatCompact(total.CacheCreationTokens)), Label: i18n.T("cache_read_ratio"), Width: statW, Color: theme.ColorMauve},
+		{Value: formatSignedUSD(total.Net), Sub: i18n.Tf("cache_premium_paid", fmt.Sprintf("%.2f", total.Premium)), Label: i18n.T("cache_net_savings"), Width: statW, Color: netColor},
+		{Value: fmt.Sprintf("$%.2f", total.WastedCost), Sub: i18n.Tf("cache_wasted_tokens", components.FormatCompact(total.WastedTokens)), Label: i18n.T("cache_wasted"), Width: statW, Color: theme.ColorPeach},
+	}
+	statRow := components.CenterBlock(components.RenderStatRow(stats, statGap), innerW)
+	lines = append(lines, statRow, "")
+
+	if len(rows) == 0 {
+		lines = append(lines, theme.MutedStyle.Render(i18n.T("no_data")))
+		card.Content = strings.Join(lines, "\n")
+		return card.Render()
+	}
+
+	type colDef struct {
+		header string
+		width  int
+		align  lipgloss.Position
+		color  lipgloss.Color
+	}
+	cols := []colDef{
+		{v.groupName(), 12, lipgloss.Left, theme.ColorGold},
+		{i18n.T("cache_create"), 7, lipgloss.Right, theme.ColorGold},
+		{i18n.T("cache_read"), 7, lipgloss.Right, theme.ColorPeach},
+		{i18n.T("cache_ratio"), 6, lipgloss.Right, theme.ColorMauve},
+		{i18n.T("cache_hit"), 5, lipgloss.Right, theme.ColorLavender},
+		{i18n.T("cache_net"), 8, lipgloss.Right, theme.ColorSkyBlue},
+		{i18n.T("cache_wasted_share"), 7, lipgloss.Right, theme.ColorBodyText},
+		{i18n.T("cache_wasted_cost"), 8, lipgloss.Right, theme.ColorBodyText},
+	}
+	sepWidth := len(cols) - 1
+	for _, c := range cols {
+		sepWidth += c.width
+	}
+	if remaining := innerW - sepWidth; remaining > 0 {
+		cols[0].width += remaining
+		sepWidth += remaining
+	}
+
+	cellStyle := func(width int, align lipgloss.Position, highlighted bool) lipgloss.Style {
+		s := lipgloss.NewStyle().Width(width).Align(align)
+		if highlighted {
+			s = s.Background(theme.ColorElevatedBg)
+		}
+		return s
+	}
+
+	var headerCells []string
+	for _, c := range cols {
+		s := cellStyle(c.width, c.align, false).Foreground(theme.ColorBrightText).Bold(true)
+		headerCells = append(headerCells, s.Render(components.TruncateText(c.header, c.width)))
+	}
+	lines = append(lines, strings.Join(headerCells, " "))
+	lines = append(lines, theme.MutedStyle.Render(strings.Repeat("─", sepWidth)))
+
+	// 8 = card border top/bottom (2) + title (1) + help line (1) + header (1)
+	//   + separator (1) + scroll indicator (1) + padding (1), plus the stats
+	visibleRows := max(height-8-lipgloss.Height(statRow)-1, 3)
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+visibleRows {
+		v.scroll = v.cursor - visibleRows + 1
+	}
+
+	for i := v.scroll; i < len(rows) && i < v.scroll+visibleRows; i++ {
+		s := rows[i]
+		hl := i == v.cursor
+		wasted := "-"
+		if s.CacheCreationTokens > 0 {
+			wasted = fmt.Sprintf("%.0f%%", float64(s.WastedTokens)/float64(s.CacheCreationTokens)*100)
+		}
+		values := []string{
+			components.TruncateText(v.label(s), cols[0].width),
+			components.FormatCompact(s.CacheCreationTokens),
+			components.FormatCompact(s.CacheReadTokens),
+			fmt.Sprintf("%.1f×", s.ReadRatio),
+			fmt.Sprintf("%.0f%%", s.HitRate*100),
+			formatSignedUSD(s.Net),
+			wasted,
+			fmt.Sprintf("$%.2f", s.WastedCost),
+		}
+		cells := make([]string, len(cols))
+		for j, c := range cols {
+			color := c.color
+			if j == 5 && s.Net < 0 {
+				color = theme.ColorWeekendRed
+			}
+			cells[j] = cellStyle(c.width, c.align, hl).Foreground(color).Render(values[j])
+		}
+		gap := " "
+		if hl {
+			gap = lipgloss.NewStyle().Background(theme.ColorElevatedBg).Render(" ")
+		}
+		lines = append(lines, strings.Join(cells, gap))
+	}
+
+	if len(rows) > visibleRows {
+		lines = append(lines, theme.MutedStyle.Render(
+			fmt.Sprintf("  [%d-%d / %d]", v.scroll+1, min(v.scroll+visibleRows, len(rows)), len(rows))))
+	}
+
+	card.Content = strings.Join(lines, "\n")
+	return card.Render()
+}
+
+// formatSignedUSD formats an amount with its sign before the dollar sign,
+// e.g. -$1.20.
+func formatSignedUSD(v float64) string {
+	if v < 0 {
+		return fmt.Sprintf("-$%.2f", -v)
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
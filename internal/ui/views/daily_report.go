@@ -29,12 +29,27 @@ var (
 	}
 )
 
+// ReportMode selects the sub-view of the Report tab.
+type ReportMode int
+
+const (
+	ReportCalendar ReportMode = iota // daily totals of one month
+	ReportHeatmap                    // weekday × hour over the filter range
+	ReportValue                      // API-equivalent cost against the plan fee
+	reportModeCount
+)
+
 type DailyReportView struct {
-	entries  []domain.UsageEntry
-	tz       *time.Location
-	year     int
-	month    time.Month
-	AnimTick uint
+	entries   []domain.UsageEntry
+	tz        *time.Location
+	year      int
+	month     time.Month
+	mode      ReportMode
+	forecast  domain.Forecast
+	anomalies map[string]domain.Anomaly // by date
+	budgets   []domain.BudgetStatus
+	value     domain.ValueReport
+	AnimTick  uint
 }
 
 func NewDailyReportView(tz *time.Location) *DailyReportView {
@@ -46,8 +61,37 @@ func (v *DailyReportView) SetData(entries []domain.UsageEntry) {
 	v.entries = entries
 }
 
+// SetForecast sets the month-end projection shown under the current
+// month.
+func (v *DailyReportView) SetForecast(f domain.Forecast) {
+	v.forecast = f
+}
+
+// SetBudgets sets the budgets shown under the current month.
+func (v *DailyReportView) SetBudgets(statuses []domain.BudgetStatus) {
+	v.budgets = statuses
+}
+
+// SetValue sets the plan value report of the value sub-view.
+func (v *DailyReportView) SetValue(r domain.ValueReport) {
+	v.value = r
+}
+
+// SetAnomalies marks the days among anomalies as outliers.
+func (v *DailyReportView) SetAnomalies(anomalies []domain.Anomaly) {
+	v.anomalies = domain.AnomalyKeys(anomalies, domain.AnomalyDay)
+}
+
 func (v *DailyReportView) Update(msg tea.Msg) tea.Cmd {
 	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.String() {
+		case "v":
+			v.mode = (v.mode + 1) % reportModeCount
+			return KeyHandledCmd
+		}
+		if v.mode != ReportCalendar {
+			return nil
+		}
 		switch km.String() {
 		case "left", "h":
 			v.month--
@@ -69,6 +113,13 @@ func (v *DailyReportView) Update(msg tea.Msg) tea.Cmd {
 }
 
 func (v *DailyReportView) Render(width, height int, compact bool) string {
+	switch v.mode {
+	case ReportHeatmap:
+		return v.renderHeatmap(width, compact)
+	case ReportValue:
+		return v.renderValue(width, compact)
+	}
+
 	agg := domain.AggregateMonthly(v.entries, v.tz, v.year, v.month)
 	cardWidth := width - 4
 
@@ -80,6 +131,8 @@ func (v *DailyReportView) Render(width, height int, compact bool) string {
 
 	innerW := card.InnerWidth()
 	var sections []string
+	sections = append(sections, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("change_month_help"))))
 
 	// 5 stat cards: Cost, Input, Output, Cache W, Cache R
 	statGap := 2
@@ -90,15 +143,28 @@ func (v *DailyReportView) Render(width, height int, compact bool) string {
 
 	stats := []components.StatCard{
 		{Value: fmt.Sprintf("$%.2f", agg.TotalCost), Label: i18n.T("cost"), Width: statW, Color: theme.ColorSkyBlue},
-		{Value: components.FormatCompact(agg.TotalInputTokens), Label: i18n.T("input_tokens"), Width: statW, Color: theme.ColorLavender},
-		{Value: components.FormatCompact(agg.TotalOutputTokens), Label: i18n.T("output_tokens"), Width: statW, Color: theme.ColorMauve},
-		{Value: components.FormatCompact(agg.TotalCacheRead), Label: i18n.T("cache_read"), Width: statW, Color: theme.ColorPeach},
-		{Value: components.FormatCompact(agg.TotalCacheCreation), Label: i18n.T("cache_create"), Width: statW, Color: theme.ColorGold},
+		{Value: components.FormatCompact(agg.InputTokens), Label: i18n.T("input_tokens"), Width: statW, Color: theme.ColorLavender},
+		{Value: components.FormatCompact(agg.OutputTokens), Label: i18n.T("output_tokens"), Width: statW, Color: theme.ColorMauve},
+		{Value: components.FormatCompact(agg.CacheReadTokens), Label: i18n.T("cache_read"), Width: statW, Color: theme.ColorPeach},
+		{Value: components.FormatCompact(agg.CacheCreationTokens), Label: i18n.T("cache_create"), Width: statW, Color: theme.ColorGold},
 	}
 
 	sections = append(sections, components.CenterBlock(components.RenderStatRow(stats, statGap), innerW))
 	sections = append(sections, "")
 
+	if m := v.forecast.Month; m.Start.Year() == v.year && m.Start.Month() == v.month && m.Cost.Projected > 0 {
+		line := i18n.Tf("forecast_month_line",
+			fmt.Sprintf("%.2f", m.Cost.Projected), fmt.Sprintf("%.2f", m.Cost.Low),
+			fmt.Sprintf("%.2f", m.Cost.High), fmt.Sprintf("%.2f", m.Cost.PerHour))
+		sections = append(sections, lipgloss.PlaceHorizontal(innerW, lipgloss.Center,
+			lipgloss.NewStyle().Foreground(theme.ColorGold).Render(line)))
+		sections = append(sections, "")
+	}
+
+	if now := time.Now().In(v.tz); now.Year() == v.year && now.Month() == v.month && len(v.budgets) > 0 {
+		sections = append(sections, budgetBars(v.budgets, innerW), "")
+	}
+
 	sections = append(sections, v.renderCalendar(agg, card.InnerWidth()))
 
 	card.Content = strings.Join(sections, "\n")
@@ -114,10 +180,7 @@ func (v *DailyReportView) renderCalendar(agg domain.MonthlyAggregate, innerWidth
 		cellWidth = 18
 	}
 
-	dayNames := []string{
-		i18n.T("day_sun"), i18n.T("day_mon"), i18n.T("day_tue"), i18n.T("day_wed"),
-		i18n.T("day_thu"), i18n.T("day_fri"), i18n.T("day_sat"),
-	}
+	dayNames := weekdayNames()
 	var headerCells []string
 	for i, d := range dayNames {
 		style := lipgloss.NewStyle().Width(cellWidth).Align(lipgloss.Center)
@@ -230,13 +293,19 @@ func (v *DailyReportView) renderWeekRow(week []int, agg domain.MonthlyAggregate,
 		if weekend {
 			dayFg = theme.ColorWeekendRed
 		}
+		// Outlying days are flagged, with their cost in red
+		costStyle := base.Foreground(theme.ColorBodyText)
+		if _, ok := v.anomalies[fmt.Sprintf("%04d-%02d-%02d", v.year, v.month, day)]; ok {
+			dayStr = "▲ " + dayStr
+			costStyle = base.Foreground(theme.ColorWeekendRed).Bold(true)
+		}
 		line1 = append(line1, base.Background(bgColor).Foreground(dayFg).Render(dayStr))
 		ds := base.Foreground(theme.ColorBodyText)
 		line2 = append(line2, ds.Render(fmtCalToken(d.InputTokens, "I")))
 		line3 = append(line3, ds.Render(fmtCalToken(d.OutputTokens, "O")))
 		line4 = append(line4, ds.Render(fmtCalToken(d.CacheReadTokens, "CR")))
 		line5 = append(line5, ds.Render(fmtCalToken(d.CacheCreationTokens, "CW")))
-		line6 = append(line6, ds.Render(fmt.Sprintf("$%.2f", d.TotalCost)))
+		line6 = append(line6, costStyle.Render(fmt.Sprintf("$%.2f", d.TotalCost)))
 		line7 = append(line7, blank)
 	}
 
@@ -246,3 +315,209 @@ func (v *DailyReportView) renderWeekRow(week []int, agg domain.MonthlyAggregate,
 
 	return place(line1) + "\n" + place(line2) + "\n" + place(line3) + "\n" + place(line4) + "\n" + place(line5) + "\n" + place(line6) + "\n" + place(line7)
 }
+
+// weekdayNames returns the translated day names, indexed by time.Weekday.
+func weekdayNames() []string {
+	return []string{
+		i18n.T("day_sun"), i18n.T("day_mon"), i18n.T("day_tue"), i18n.T("day_wed"),
+		i18n.T("day_thu"), i18n.T("day_fri"), i18n.T("day_sat"),
+	}
+}
+
+// renderHeatmap renders usage by weekday and hour over the whole filter
+// range, shaded by cost, followed by the busiest slots.
+func (v *DailyReportView) renderHeatmap(width int, compact bool) string {
+	hm := domain.AggregateHeatmap(v.entries, v.tz)
+
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.T("usage_heatmap"), v.AnimTick),
+		Width:   width - 4,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	var sections []string
+	sections = append(sections, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("heatmap_help"))))
+
+	if hm.MaxCost == 0 {
+		sections = append(sections, theme.MutedStyle.Render(i18n.T("no_data")))
+		card.Content = strings.Join(sections, "\n")
+		return card.Render()
+	}
+
+	const labelW = 5
+	cellW := (innerW - labelW) / 24
+	if cellW < 1 {
+		cellW = 1
+	}
+	if cellW > 4 {
+		cellW = 4
+	}
+
+	// Hour axis, labelled every three hours
+	var axis strings.Builder
+	axis.WriteString(strings.Repeat(" ", labelW))
+	for h := 0; h < 24; h += 3 {
+		axis.WriteString(fmt.Sprintf("%-*d", cellW*3, h))
+	}
+	var grid []string
+	grid = append(grid, theme.MutedStyle.Render(axis.String()))
+
+	dayNames := weekdayNames()
+	for d, name := range dayNames {
+		labelStyle := lipgloss.NewStyle().Width(labelW).Foreground(theme.ColorMutedText)
+		if isWeekend(d) {
+			labelStyle = labelStyle.Foreground(theme.ColorWeekendRed)
+		}
+		var row strings.Builder
+		row.WriteString(labelStyle.Render(name))
+		for h := 0; h < 24; h++ {
+			row.WriteString(components.HeatCell(hm.Cells[d][h].Cost/hm.MaxCost, cellW))
+		}
+		grid = append(grid, row.String())
+	}
+	grid = append(grid, "")
+	grid = append(grid, strings.Repeat(" ", labelW)+theme.MutedStyle.Render("$0 ")+
+		components.HeatLegend(8)+theme.MutedStyle.Render(fmt.Sprintf(" $%.2f", hm.MaxCost)))
+
+	sections = append(sections, components.CenterBlock(strings.Join(grid, "\n"), innerW))
+	sections = append(sections, "")
+
+	// Busiest slots
+	sections = append(sections, lipgloss.NewStyle().Foreground(theme.ColorBrightText).Bold(true).Render(i18n.T("busiest_hours")))
+	for _, p := range hm.Peaks(5) {
+		sections = append(sections, fmt.Sprintf("  %s %02d:00  %s  %s",
+			lipgloss.NewStyle().Width(10).Render(weekdayNames()[p.Day]),
+			p.Hour,
+			lipgloss.NewStyle().Foreground(theme.ColorSkyBlue).Render(fmt.Sprintf("%9s", fmt.Sprintf("$%.2f", p.Cost))),
+			theme.MutedStyle.Render(fmt.Sprintf("%s %s · %d %s",
+				components.FormatCompact(p.Tokens), i18n.T("tokens"), p.Messages, i18n.T("msgs")))))
+	}
+
+	card.Content = strings.Join(sections, "\n")
+	return card.Render()
+}
+
+// valueCycles and valueProjects cap the rows of the value sub-view.
+const (
+	valueCycles   = 12
+	valueProjects = 8
+)
+
+// renderValue compares the API-equivalent cost of each billing cycle with
+// the plan fee, followed by the projects of the latest cycle.
+func (v *DailyReportView) renderValue(width int, compact bool) string {
+	r := v.value
+	title := i18n.T("plan_value")
+	if r.Plan != "" {
+		title += " · " + r.Plan
+	}
+	if r.Price > 0 {
+		title += fmt.Sprintf(" ($%.0f/mo)", r.Price)
+	}
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(title, v.AnimTick),
+		Width:   width - 4,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	var sections []string
+	sections = append(sections, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("heatmap_help"))))
+	if r.Price <= 0 {
+		sections = append(sections, theme.MutedStyle.Render(i18n.T("plan_not_set")))
+		card.Content = strings.Join(sections, "\n")
+		return card.Render()
+	}
+	if len(r.Cycles) == 0 {
+		sections = append(sections, theme.MutedStyle.Render(i18n.T("no_data")))
+		card.Content = strings.Join(sections, "\n")
+		return card.Render()
+	}
+
+	// Stat cards for the latest cycle
+	latest := r.Cycles[0]
+	breakEven := "—"
+	if latest.Day > 0 {
+		breakEven = i18n.Tf("cycle_day", latest.Day)
+	}
+	savingsColor := theme.ColorGold
+	if latest.Savings < 0 {
+		savingsColor = theme.ColorWeekendRed
+	}
+	statGap := 2
+	statW := max((innerW-statGap*4)/5, 10)
+	stats := []components.StatCard{
+		{Value: fmt.Sprintf("$%.2f", latest.Cost), Label: i18n.T("api_cost"), Width: statW, Color: theme.ColorSkyBlue},
+		{Value: fmt.Sprintf("$%.2f", latest.Fee), Label: i18n.T("plan_fee"), Width: statW, Color: theme.ColorLavender},
+		{Value: fmt.Sprintf("$%.2f", latest.Savings), Label: i18n.T("savings"), Width: statW, Color: savingsColor},
+		{Value: fmt.Sprintf("%.1f×", latest.Multiple), Label: i18n.T("value_multiple"), Width: statW, Color: theme.ColorMauve},
+		{Value: breakEven, Label: i18n.T("break_even"), Width: statW, Color: theme.ColorPeach},
+	}
+	sections = append(sections, components.CenterBlock(components.RenderStatRow(stats, statGap), innerW), "")
+
+	// One row per cycle
+	cell := func(text string, w int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(w).Align(align).Foreground(color).Render(text)
+	}
+	const cycleW, numW = 25, 12
+	header := strings.Join([]string{
+		cell(i18n.T("billing_cycle"), cycleW, lipgloss.Left, theme.ColorBrightText),
+		cell(i18n.T("api_cost"), numW, lipgloss.Right, theme.ColorSkyBlue),
+		cell(i18n.T("plan_fee"), numW, lipgloss.Right, theme.ColorLavender),
+		cell(i18n.T("savings"), numW, lipgloss.Right, theme.ColorGold),
+		cell(i18n.T("value_multiple"), numW, lipgloss.Right, theme.ColorMauve),
+		cell(i18n.T("break_even"), numW, lipgloss.Right, theme.ColorPeach),
+	}, " ")
+	sections = append(sections, lipgloss.NewStyle().Bold(true).Render(header),
+		theme.MutedStyle.Render(strings.Repeat("─", cycleW+numW*5+5)))
+	for i, c := range r.Cycles {
+		if i == valueCycles {
+			break
+		}
+		label := c.Start.Format("Jan 02") + " – " + c.End.AddDate(0, 0, -1).Format("Jan 02 2006")
+		day := "—"
+		if c.Day > 0 {
+			day = c.BreakEven.Format("Jan 02")
+		}
+		savingsColor := theme.ColorGold
+		if c.Savings < 0 {
+			savingsColor = theme.ColorWeekendRed
+		}
+		sections = append(sections, strings.Join([]string{
+			cell(label, cycleW, lipgloss.Left, theme.ColorBodyText),
+			cell(fmt.Sprintf("$%.2f", c.Cost), numW, lipgloss.Right, theme.ColorSkyBlue),
+			cell(fmt.Sprintf("$%.2f", c.Fee), numW, lipgloss.Right, theme.ColorLavender),
+			cell(fmt.Sprintf("$%.2f", c.Savings), numW, lipgloss.Right, savingsColor),
+			cell(fmt.Sprintf("%.1f×", c.Multiple), numW, lipgloss.Right, theme.ColorMauve),
+			cell(day, numW, lipgloss.Right, theme.ColorPeach),
+		}, " "))
+	}
+	sections = append(sections, "", theme.MutedStyle.Render(i18n.Tf("value_total",
+		fmt.Sprintf("%.2f", r.Cost), fmt.Sprintf("%.2f", r.Fees), r.Multiple)), "")
+
+	// Each project's share of the latest cycle's value
+	sections = append(sections, lipgloss.NewStyle().Foreground(theme.ColorBrightText).Bold(true).Render(i18n.T("value_by_project")))
+	const nameW, costW, pctW = 24, 10, 6
+	barW := max(innerW-nameW-costW-pctW-3, 10)
+	for i, p := range latest.Projects {
+		if i == valueProjects {
+			break
+		}
+		name := p.Project
+		if name == "" {
+			name = "-"
+		}
+		sections = append(sections, strings.Join([]string{
+			cell(components.TruncateText(name, nameW), nameW, lipgloss.Left, theme.ColorBodyText),
+			components.ProgressBar(p.Share, barW),
+			cell(fmt.Sprintf("$%.2f", p.Cost), costW, lipgloss.Right, theme.ColorSkyBlue),
+			cell(fmt.Sprintf("%.0f%%", p.Share*100), pctW, lipgloss.Right, theme.ColorBodyText),
+		}, " "))
+	}
+
+	card.Content = strings.Join(sections, "\n")
+	return card.Render()
+}
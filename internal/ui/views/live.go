@@ -7,6 +7,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/anomredux/claude-smi/internal/api"
 	"github.com/anomredux/claude-smi/internal/domain"
 	"github.com/anomredux/claude-smi/internal/i18n"
@@ -20,9 +21,12 @@ type LiveView struct {
 	blocks       []domain.SessionBlock
 	daily        []domain.DailyAggregate
 	tz *time.Location
-	calc     *pricing.Calculator
-	apiUsage *api.UsageData
-	AnimTick uint
+	calc        *pricing.Calculator
+	apiUsage    *api.UsageData
+	forecast    domain.Forecast
+	fiveHourCap *domain.CapEstimate // estimated 5-hour cap; nil if unknown
+	budgets     []domain.BudgetStatus
+	AnimTick    uint
 
 	// Cached burn rate (recomputed only on data change)
 	burn burnCache
@@ -62,6 +66,23 @@ func (v *LiveView) SetApiUsage(data *api.UsageData) {
 	}
 }
 
+// SetForecast sets the projections of the Forecast card.
+func (v *LiveView) SetForecast(f domain.Forecast) {
+	v.forecast = f
+}
+
+// SetCap sets the estimated 5-hour cap shown under the 5h gauge; nil
+// hides it.
+func (v *LiveView) SetCap(c *domain.CapEstimate) {
+	v.fiveHourCap = c
+}
+
+// SetBudgets sets the budgets of the Budgets card, which is hidden
+// without any.
+func (v *LiveView) SetBudgets(statuses []domain.BudgetStatus) {
+	v.budgets = statuses
+}
+
 func (v *LiveView) recomputeBurn() {
 	sEntries := v.sessionEntries()
 	v.cachedSessionEntries = sEntries
@@ -107,15 +128,28 @@ func (v *LiveView) Render(width, height int, compact bool) string {
 	sections = append(sections, v.renderSessionTimer(cardWidth, compact))
 	sections = append(sections, v.renderUtilization(cardWidth, compact))
 	sections = append(sections, v.renderBurnRate(cardWidth, compact))
+	sections = append(sections, v.renderForecast(cardWidth, compact))
+	if len(v.budgets) > 0 {
+		sections = append(sections, v.renderBudgets(cardWidth, compact))
+	}
 	sections = append(sections, v.renderModelBreakdown(cardWidth, compact))
 
 	return strings.Join(sections, "\n")
 }
 
 // sessionTimes returns normalized session start/end times and remaining duration.
-// The API's resets_at jitters between nn:00 and nn:59, so we round to the
-// nearest hour to get stable boundaries: nn:00 start → nn+4:59 end.
+// The active block is the source of truth; it is anchored to the API's
+// window when one was observed. Without one, the API's resets_at is used,
+// rounded to the nearest hour as it jitters between nn:00 and nn:59:
+// nn:00 start → nn+4:59 end.
 func (v *LiveView) sessionTimes() (start, end time.Time, remaining time.Duration, ok bool) {
+	if block := v.activeBlock(); block != nil {
+		remaining = time.Until(block.EndTime)
+		if remaining < 0 {
+			remaining = 0
+		}
+		return block.StartTime, block.EndTime.Add(-time.Minute), remaining, true
+	}
 	if v.apiUsage == nil {
 		return time.Time{}, time.Time{}, 0, false
 	}
@@ -133,8 +167,12 @@ func (v *LiveView) sessionTimes() (start, end time.Time, remaining time.Duration
 	return start, end, remaining, true
 }
 
-// sessionEntries returns entries filtered to the current API session window.
+// sessionEntries returns the entries of the active block or, without one,
+// the entries since the start of the current API session window.
 func (v *LiveView) sessionEntries() []domain.UsageEntry {
+	if block := v.activeBlock(); block != nil {
+		return block.Entries
+	}
 	if v.apiUsage != nil {
 		sessionStart, err := v.apiUsage.SessionStart()
 		if err == nil {
@@ -147,9 +185,6 @@ func (v *LiveView) sessionEntries() []domain.UsageEntry {
 			return filtered
 		}
 	}
-	if block := v.activeBlock(); block != nil {
-		return block.Entries
-	}
 	return nil
 }
 
@@ -244,6 +279,7 @@ func (v *LiveView) renderUtilization(cardWidth int, compact bool) string {
 			Label:   i18n.T("five_hour"),
 			Percent: fiveHourPct,
 			Width:   gaugeW,
+			Note:    v.capNote(),
 		},
 		{
 			Label:   i18n.T("seven_day"),
@@ -257,6 +293,16 @@ func (v *LiveView) renderUtilization(cardWidth int, compact bool) string {
 	return card.Render()
 }
 
+// capNote estimates the tokens left in the 5-hour window from the
+// estimated cap.
+func (v *LiveView) capNote() string {
+	if v.fiveHourCap == nil {
+		return ""
+	}
+	left := v.fiveHourCap.TokensLeft(v.apiUsage.FiveHour.Utilization)
+	return i18n.Tf("tokens_left", components.FormatCompact(int(left)))
+}
+
 // ── Section 3: Burn Rate — 4 Stat Cards (session-filtered) ──
 
 func (v *LiveView) renderBurnRate(cardWidth int, compact bool) string {
@@ -334,7 +380,80 @@ func (v *LiveView) renderBurnRate(cardWidth int, compact bool) string {
 	return card.Render()
 }
 
-// ── Section 4: Model Breakdown — Pie Chart (session-filtered) ──
+// ── Section 4: Forecast — Today / Month / 7-Day Window ──
+
+func (v *LiveView) renderForecast(cardWidth int, compact bool) string {
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.T("forecast"), v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+
+	f := v.forecast
+	if f.Month.Cost.Actual == 0 && f.Month.Cost.PerHour == 0 {
+		card.Content = theme.MutedStyle.Render(i18n.T("no_data"))
+		return card.Render()
+	}
+
+	innerW := card.InnerWidth()
+	statGap := 2
+	n := 2
+	if f.Week != nil {
+		n = 3
+	}
+	statW := (innerW - statGap*(n-1)) / n
+	if statW < 14 {
+		statW = 14
+	}
+
+	costBand := func(e domain.Estimate) string {
+		return fmt.Sprintf("$%.2f – $%.2f", e.Low, e.High)
+	}
+	stats := []components.StatCard{
+		{
+			Value: fmt.Sprintf("$%.2f", f.Today.Cost.Projected),
+			Sub:   costBand(f.Today.Cost),
+			Label: i18n.Tf("forecast_today", fmt.Sprintf("%.2f", f.Today.Cost.Actual)),
+			Width: statW,
+			Color: theme.ColorSkyBlue,
+		},
+		{
+			Value: fmt.Sprintf("$%.2f", f.Month.Cost.Projected),
+			Sub:   costBand(f.Month.Cost),
+			Label: i18n.Tf("forecast_month", fmt.Sprintf("%.2f", f.Month.Cost.Actual)),
+			Width: statW,
+			Color: theme.ColorMauve,
+		},
+	}
+	if w := f.Week; w != nil {
+		stats = append(stats, components.StatCard{
+			Value: components.FormatCompact(int(w.Tokens.Projected)),
+			Sub:   components.FormatCompact(int(w.Tokens.Low)) + " – " + components.FormatCompact(int(w.Tokens.High)),
+			Label: i18n.Tf("forecast_week", w.End.In(v.tz).Format("Mon 15:04")),
+			Width: statW,
+			Color: theme.ColorGold,
+		})
+	}
+
+	note := theme.MutedStyle.Render(i18n.Tf("forecast_note", fmt.Sprintf("%.2f", f.Month.Cost.PerHour)))
+	card.Content = components.CenterBlock(components.RenderStatRow(stats, statGap), innerW) + "\n\n" +
+		lipgloss.PlaceHorizontal(innerW, lipgloss.Center, note)
+	return card.Render()
+}
+
+// ── Section 5: Budgets — one bar per configured limit ──
+
+func (v *LiveView) renderBudgets(cardWidth int, compact bool) string {
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.T("budgets"), v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	card.Content = budgetBars(v.budgets, card.InnerWidth())
+	return card.Render()
+}
+
+// ── Section 6: Model Breakdown — Pie Chart (session-filtered) ──
 
 func (v *LiveView) renderModelBreakdown(cardWidth int, compact bool) string {
 	card := components.Card{
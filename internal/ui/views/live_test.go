@@ -153,3 +153,26 @@ func TestRecomputeBurn_Empty(t *testing.T) {
 		t.Error("expected no burn data for empty entries")
 	}
 }
+
+func TestSessionTimes_PrefersActiveBlock(t *testing.T) {
+	v := NewLiveView(time.UTC, nil)
+
+	start := time.Now().UTC().Truncate(time.Hour).Add(-time.Hour)
+	v.apiUsage = &api.UsageData{
+		FiveHour: api.WindowData{ResetsAt: start.Add(2 * time.Hour).Format(time.RFC3339)},
+	}
+	v.blocks = []domain.SessionBlock{{
+		StartTime: start,
+		EndTime:   start.Add(domain.BlockDuration),
+		Status:    domain.BlockActive,
+		Entries:   []domain.UsageEntry{{Timestamp: start.Add(time.Minute)}},
+	}}
+
+	got, _, _, ok := v.sessionTimes()
+	if !ok || !got.Equal(start) {
+		t.Errorf("sessionTimes start = %v, %v; want %v from the active block", got, ok, start)
+	}
+	if n := len(v.sessionEntries()); n != 1 {
+		t.Errorf("sessionEntries = %d entries, want the active block's 1", n)
+	}
+}
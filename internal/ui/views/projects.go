@@ -0,0 +1,275 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// ProjectSort selects the column the projects list is ordered by.
+type ProjectSort int
+
+const (
+	ProjectSortCost ProjectSort = iota
+	ProjectSortInput
+	ProjectSortOutput
+	ProjectSortCacheCreate
+	ProjectSortCacheRead
+	ProjectSortDays
+	ProjectSortSessions
+	ProjectSortLast
+	ProjectSortName
+	projectSortCount
+)
+
+// ProjectsView ranks projects by usage. Enter asks the app to filter by
+// the project under the cursor.
+type ProjectsView struct {
+	projects  []domain.ProjectAggregate
+	active    map[string]bool
+	tz        *time.Location
+	sortBy    ProjectSort
+	ascending bool
+	cursor    int
+	scroll    int
+	Picked    string // project root chosen with enter; cleared by the app
+	AnimTick  uint
+}
+
+func NewProjectsView(tz *time.Location) *ProjectsView {
+	return &ProjectsView{tz: tz}
+}
+
+// SetData takes the project aggregates and the roots of the projects the
+// app currently filters by.
+func (v *ProjectsView) SetData(projects []domain.ProjectAggregate, active map[string]bool) {
+	v.projects = projects
+	v.active = active
+	v.sort()
+	if v.cursor >= len(projects) {
+		v.cursor = max(0, len(projects)-1)
+	}
+}
+
+// sort orders the projects by the selected column, breaking ties by cost
+// and then name.
+func (v *ProjectsView) sort() {
+	less := func(a, b domain.ProjectAggregate) bool {
+		switch v.sortBy {
+		case ProjectSortInput:
+			if a.InputTokens != b.InputTokens {
+				return a.InputTokens > b.InputTokens
+			}
+		case ProjectSortOutput:
+			if a.OutputTokens != b.OutputTokens {
+				return a.OutputTokens > b.OutputTokens
+			}
+		case ProjectSortCacheCreate:
+			if a.CacheCreationTokens != b.CacheCreationTokens {
+				return a.CacheCreationTokens > b.CacheCreationTokens
+			}
+		case ProjectSortCacheRead:
+			if a.CacheReadTokens != b.CacheReadTokens {
+				return a.CacheReadTokens > b.CacheReadTokens
+			}
+		case ProjectSortDays:
+			if a.ActiveDays != b.ActiveDays {
+				return a.ActiveDays > b.ActiveDays
+			}
+		case ProjectSortSessions:
+			if a.Sessions != b.Sessions {
+				return a.Sessions > b.Sessions
+			}
+		case ProjectSortLast:
+			if !a.LastActivity.Equal(b.LastActivity) {
+				return a.LastActivity.After(b.LastActivity)
+			}
+		case ProjectSortName:
+			if a.ProjectName != b.ProjectName {
+				return a.ProjectName < b.ProjectName
+			}
+		}
+		if a.TotalCost != b.TotalCost {
+			return a.TotalCost > b.TotalCost
+		}
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		return a.ProjectPath < b.ProjectPath
+	}
+	sort.SliceStable(v.projects, func(i, j int) bool {
+		if v.ascending {
+			return less(v.projects[j], v.projects[i])
+		}
+		return less(v.projects[i], v.projects[j])
+	})
+}
+
+func (v *ProjectsView) Update(msg tea.Msg) tea.Cmd {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	switch km.String() {
+	case "j", "down":
+		if v.cursor < len(v.projects)-1 {
+			v.cursor++
+		}
+		return KeyHandledCmd
+	case "k", "up":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+		return KeyHandledCmd
+	case "o":
+		v.sortBy = (v.sortBy + 1) % projectSortCount
+		v.sort()
+		v.cursor = 0
+		return KeyHandledCmd
+	case "O":
+		v.ascending = !v.ascending
+		v.sort()
+		v.cursor = 0
+		return KeyHandledCmd
+	case "enter":
+		if v.cursor < len(v.projects) {
+			v.Picked = v.projects[v.cursor].ProjectPath
+		}
+		return KeyHandledCmd
+	}
+	return nil
+}
+
+func (v *ProjectsView) Render(width, height int, compact bool) string {
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(fmt.Sprintf("%s (%d)", i18n.T("projects"), len(v.projects)), v.AnimTick),
+		Width:   width - 4,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	if len(v.projects) == 0 {
+		card.Content = theme.MutedStyle.Render(i18n.T("no_projects_found"))
+		return card.Render()
+	}
+
+	type colDef struct {
+		header string
+		sortBy ProjectSort
+		width  int
+		align  lipgloss.Position
+		color  lipgloss.Color
+	}
+	cols := []colDef{
+		{i18n.T("project"), ProjectSortName, 12, lipgloss.Left, theme.ColorGold},
+		{i18n.T("input_tokens"), ProjectSortInput, 8, lipgloss.Right, theme.ColorLavender},
+		{i18n.T("output_tokens"), ProjectSortOutput, 8, lipgloss.Right, theme.ColorMauve},
+		{i18n.T("cache_create"), ProjectSortCacheCreate, 8, lipgloss.Right, theme.ColorGold},
+		{i18n.T("cache_read"), ProjectSortCacheRead, 8, lipgloss.Right, theme.ColorPeach},
+		{i18n.T("cost"), ProjectSortCost, 9, lipgloss.Right, theme.ColorSkyBlue},
+		{i18n.T("share"), -1, 6, lipgloss.Right, theme.ColorSkyBlue}, // follows cost
+		{i18n.T("active_days"), ProjectSortDays, 5, lipgloss.Right, theme.ColorBodyText},
+		{i18n.T("sessions"), ProjectSortSessions, 9, lipgloss.Right, theme.ColorBodyText},
+		{i18n.T("last_activity"), ProjectSortLast, 12, lipgloss.Right, theme.ColorBodyText},
+	}
+	sepWidth := len(cols) - 1
+	for _, c := range cols {
+		sepWidth += c.width
+	}
+	if remaining := innerW - sepWidth; remaining > 0 {
+		cols[0].width += remaining
+		sepWidth += remaining
+	}
+
+	cellStyle := func(width int, align lipgloss.Position, highlighted bool) lipgloss.Style {
+		s := lipgloss.NewStyle().Width(width).Align(align)
+		if highlighted {
+			s = s.Background(theme.ColorElevatedBg)
+		}
+		return s
+	}
+
+	var rows []string
+	rows = append(rows, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("projects_help"))))
+
+	// Header; the sort column is marked with its direction
+	arrow := "↓"
+	if v.ascending {
+		arrow = "↑"
+	}
+	var headerCells []string
+	for _, c := range cols {
+		text := c.header
+		s := cellStyle(c.width, c.align, false).Foreground(theme.ColorBrightText).Bold(true)
+		if c.sortBy == v.sortBy {
+			text += arrow
+			s = s.Foreground(theme.ColorGold)
+		}
+		headerCells = append(headerCells, s.Render(components.TruncateText(text, c.width)))
+	}
+	rows = append(rows, strings.Join(headerCells, " "))
+	rows = append(rows, theme.MutedStyle.Render(strings.Repeat("─", sepWidth)))
+
+	// 8 = card border top/bottom (2) + title (1) + help line (1)
+	//   + header (1) + separator (1) + scroll indicator (1) + padding (1)
+	visibleRows := height - 8
+	if visibleRows < 3 {
+		visibleRows = 3
+	}
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+visibleRows {
+		v.scroll = v.cursor - visibleRows + 1
+	}
+
+	for i := v.scroll; i < len(v.projects) && i < v.scroll+visibleRows; i++ {
+		p := v.projects[i]
+		hl := i == v.cursor
+		name := p.ProjectName
+		if name == "" {
+			name = "-"
+		}
+		if v.active[p.ProjectPath] {
+			name = "● " + name
+		}
+		values := []string{
+			components.TruncateText(name, cols[0].width),
+			components.FormatCompact(p.InputTokens),
+			components.FormatCompact(p.OutputTokens),
+			components.FormatCompact(p.CacheCreationTokens),
+			components.FormatCompact(p.CacheReadTokens),
+			fmt.Sprintf("$%.2f", p.TotalCost),
+			fmt.Sprintf("%.1f%%", p.Share),
+			fmt.Sprintf("%d", p.ActiveDays),
+			fmt.Sprintf("%d", p.Sessions),
+			p.LastActivity.In(v.tz).Format("Jan 02 15:04"),
+		}
+		cells := make([]string, len(cols))
+		for j, c := range cols {
+			cells[j] = cellStyle(c.width, c.align, hl).Foreground(c.color).Render(values[j])
+		}
+		gap := " "
+		if hl {
+			gap = lipgloss.NewStyle().Background(theme.ColorElevatedBg).Render(" ")
+		}
+		rows = append(rows, strings.Join(cells, gap))
+	}
+
+	if len(v.projects) > visibleRows {
+		rows = append(rows, theme.MutedStyle.Render(
+			fmt.Sprintf("  [%d-%d / %d]", v.scroll+1, min(v.scroll+visibleRows, len(v.projects)), len(v.projects))))
+	}
+
+	card.Content = strings.Join(rows, "\n")
+	return card.Render()
+}
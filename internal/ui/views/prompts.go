@@ -0,0 +1,109 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// maxPromptSessions caps the sessions listed, newest first.
+const maxPromptSessions = 30
+
+// PromptsView lists the most expensive prompts of each session.
+type PromptsView struct {
+	sessions []domain.SessionPrompts
+	tz       *time.Location
+	AnimTick uint
+}
+
+func NewPromptsView(tz *time.Location) *PromptsView {
+	return &PromptsView{tz: tz}
+}
+
+// SetData takes sessions ordered newest first, each with its top prompts.
+func (v *PromptsView) SetData(sessions []domain.SessionPrompts) {
+	v.sessions = sessions
+}
+
+func (v *PromptsView) Update(msg tea.Msg) tea.Cmd {
+	return nil
+}
+
+func (v *PromptsView) Render(width, height int, compact bool) string {
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.T("top_prompts"), v.AnimTick),
+		Width:   width - 4,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	if len(v.sessions) == 0 {
+		card.Content = theme.MutedStyle.Render(i18n.T("no_prompts_found"))
+		return card.Render()
+	}
+
+	colCost := 9
+	colCalls := 7
+	colTokens := 8
+	colDur := 8
+	colPreview := innerW - colCost - colCalls - colTokens - colDur - 4 - 2 // gaps + indent
+	if colPreview < 10 {
+		colPreview = 10
+	}
+
+	cell := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Render(text)
+	}
+
+	var rows []string
+	rows = append(rows, "  "+strings.Join([]string{
+		lipgloss.NewStyle().Width(colCost).Align(lipgloss.Right).Foreground(theme.ColorSkyBlue).Bold(true).Render(i18n.T("cost")),
+		lipgloss.NewStyle().Width(colCalls).Align(lipgloss.Right).Foreground(theme.ColorLavender).Bold(true).Render(i18n.T("calls")),
+		lipgloss.NewStyle().Width(colTokens).Align(lipgloss.Right).Foreground(theme.ColorMauve).Bold(true).Render(i18n.T("tokens")),
+		lipgloss.NewStyle().Width(colDur).Align(lipgloss.Right).Foreground(theme.ColorPeach).Bold(true).Render(i18n.T("duration")),
+		lipgloss.NewStyle().Width(colPreview).Align(lipgloss.Left).Foreground(theme.ColorBrightText).Bold(true).Render(i18n.T("prompt")),
+	}, " "))
+
+	for i, s := range v.sessions {
+		if i == maxPromptSessions {
+			rows = append(rows, "", theme.MutedStyle.Render(i18n.Tf("more_sessions", len(v.sessions)-i)))
+			break
+		}
+
+		id := s.SessionID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		name := s.ProjectName
+		if name == "" {
+			name = "-"
+		}
+		header := fmt.Sprintf("%s  %s  %s  %s",
+			lipgloss.NewStyle().Foreground(theme.ColorGold).Bold(true).Render(name),
+			theme.MutedStyle.Render(id),
+			theme.BodyStyle.Render(s.LastActive.In(v.tz).Format("Jan 02 15:04")),
+			theme.MutedStyle.Render(i18n.Tf("session_prompts_total", s.PromptCount, s.TotalCost)))
+		rows = append(rows, "", header)
+
+		for j, p := range s.Prompts {
+			row := strings.Join([]string{
+				cell(fmt.Sprintf("$%.2f", p.TotalCost), colCost, lipgloss.Right, theme.ColorSkyBlue),
+				cell(components.FormatNumber(p.Calls), colCalls, lipgloss.Right, theme.ColorLavender),
+				cell(components.FormatCompact(p.TotalTokens()), colTokens, lipgloss.Right, theme.ColorMauve),
+				cell(components.FormatDuration(p.Duration), colDur, lipgloss.Right, theme.ColorPeach),
+				cell(components.TruncateText(p.Preview, colPreview), colPreview, lipgloss.Left, theme.ColorBodyText),
+			}, " ")
+			rows = append(rows, "  "+components.RowBackground(j).Render(row))
+		}
+	}
+
+	card.Content = strings.Join(rows, "\n")
+	return card.Render()
+}
@@ -0,0 +1,370 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// SessionSort selects the column the sessions list is ordered by.
+type SessionSort int
+
+const (
+	SessionSortLast     SessionSort = iota // last activity
+	SessionSortCost                        // total cost
+	SessionSortTokens                      // total tokens
+	SessionSortDuration                    // first to last activity
+	SessionSortMessages                    // message count
+	sessionSortCount
+)
+
+// sessionSortKeys are the i18n keys of the sort columns.
+var sessionSortKeys = [sessionSortCount]string{"last_activity", "cost", "tokens", "duration", "msgs"}
+
+// maxSessionEntries caps the entries listed in the session detail.
+const maxSessionEntries = 200
+
+// SessionsView lists Claude Code conversations with a drill-down into the
+// entries of one.
+type SessionsView struct {
+	sessions    []domain.SessionAggregate
+	entries     []domain.UsageEntry
+	tz          *time.Location
+	sortBy      SessionSort
+	ascending   bool
+	cursor      int
+	detail      bool
+	scroll      int
+	ScrollReset bool // signals app to reset scroll offset
+	AnimTick    uint
+}
+
+func NewSessionsView(tz *time.Location) *SessionsView {
+	return &SessionsView{tz: tz}
+}
+
+// SetData takes the session aggregates and the entries they were built
+// from, sorted by timestamp.
+func (v *SessionsView) SetData(sessions []domain.SessionAggregate, entries []domain.UsageEntry) {
+	v.sessions = sessions
+	v.entries = entries
+	v.sort()
+	if v.cursor >= len(sessions) {
+		v.cursor = max(0, len(sessions)-1)
+	}
+}
+
+// sort orders the sessions by the selected column, breaking ties by last
+// activity.
+func (v *SessionsView) sort() {
+	less := func(a, b domain.SessionAggregate) bool {
+		switch v.sortBy {
+		case SessionSortCost:
+			if a.TotalCost != b.TotalCost {
+				return a.TotalCost > b.TotalCost
+			}
+		case SessionSortTokens:
+			if a.TotalTokens() != b.TotalTokens() {
+				return a.TotalTokens() > b.TotalTokens()
+			}
+		case SessionSortDuration:
+			if a.Duration != b.Duration {
+				return a.Duration > b.Duration
+			}
+		case SessionSortMessages:
+			if a.MessageCount != b.MessageCount {
+				return a.MessageCount > b.MessageCount
+			}
+		}
+		if !a.LastActivity.Equal(b.LastActivity) {
+			return a.LastActivity.After(b.LastActivity)
+		}
+		return a.SessionID < b.SessionID
+	}
+	sort.SliceStable(v.sessions, func(i, j int) bool {
+		if v.ascending {
+			return less(v.sessions[j], v.sessions[i])
+		}
+		return less(v.sessions[i], v.sessions[j])
+	})
+}
+
+func (v *SessionsView) Update(msg tea.Msg) tea.Cmd {
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return nil
+	}
+	if v.detail {
+		// Detail mode: only consume esc/backspace (back to list).
+		switch km.String() {
+		case "esc", "backspace":
+			v.detail = false
+			v.ScrollReset = true
+			return KeyHandledCmd
+		}
+		return nil
+	}
+
+	switch km.String() {
+	case "j", "down":
+		if v.cursor < len(v.sessions)-1 {
+			v.cursor++
+		}
+		return KeyHandledCmd
+	case "k", "up":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+		return KeyHandledCmd
+	case "o":
+		v.sortBy = (v.sortBy + 1) % sessionSortCount
+		v.sort()
+		v.cursor = 0
+		return KeyHandledCmd
+	case "O":
+		v.ascending = !v.ascending
+		v.sort()
+		v.cursor = 0
+		return KeyHandledCmd
+	case "enter":
+		if len(v.sessions) > 0 {
+			v.detail = true
+			v.ScrollReset = true
+		}
+		return KeyHandledCmd
+	}
+	return nil
+}
+
+// InDetail returns whether the view is in detail mode.
+func (v *SessionsView) InDetail() bool {
+	return v.detail
+}
+
+// sortLabel describes the current ordering, e.g. "cost ↓".
+func (v *SessionsView) sortLabel() string {
+	arrow := "↓"
+	if v.ascending {
+		arrow = "↑"
+	}
+	return fmt.Sprintf("%s %s", strings.ToLower(i18n.T(sessionSortKeys[v.sortBy])), arrow)
+}
+
+func (v *SessionsView) Render(width, height int, compact bool) string {
+	cardWidth := width - 4
+
+	if len(v.sessions) == 0 {
+		card := components.Card{
+			Title:   theme.AnimatedGradientText(i18n.T("sessions"), v.AnimTick),
+			Width:   cardWidth,
+			Compact: compact,
+		}
+		card.Content = theme.MutedStyle.Render(i18n.T("no_sessions_found"))
+		return card.Render()
+	}
+
+	if v.detail && v.cursor < len(v.sessions) {
+		return v.renderDetail(v.sessions[v.cursor], cardWidth, compact)
+	}
+	return v.renderList(cardWidth, height, compact)
+}
+
+func (v *SessionsView) renderList(cardWidth, contentHeight int, compact bool) string {
+	title := fmt.Sprintf("%s (%d) · %s", i18n.T("sessions"), len(v.sessions), v.sortLabel())
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(title, v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	colProject := 14
+	colStart := 12
+	colLast := 12
+	colDur := 8
+	colMsgs := 6
+	colTokens := 8
+	colCost := 9
+	colGaps := 7
+	colModels := innerW - colProject - colStart - colLast - colDur - colMsgs - colTokens - colCost - colGaps
+	if colModels < 10 {
+		colModels = 10
+	}
+
+	cellStyle := func(width int, align lipgloss.Position, highlighted bool) lipgloss.Style {
+		s := lipgloss.NewStyle().Width(width).Align(align)
+		if highlighted {
+			s = s.Background(theme.ColorElevatedBg)
+		}
+		return s
+	}
+	header := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return cellStyle(width, align, false).Foreground(color).Bold(true).Render(text)
+	}
+
+	var rows []string
+	rows = append(rows, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("sessions_help"))))
+	rows = append(rows, strings.Join([]string{
+		header(i18n.T("project"), colProject, lipgloss.Left, theme.ColorBrightText),
+		header(i18n.T("start"), colStart, lipgloss.Left, theme.ColorBrightText),
+		header(i18n.T("last_activity"), colLast, lipgloss.Left, theme.ColorBrightText),
+		header(i18n.T("duration"), colDur, lipgloss.Right, theme.ColorPeach),
+		header(i18n.T("models"), colModels, lipgloss.Left, theme.ColorBrightText),
+		header(i18n.T("msgs"), colMsgs, lipgloss.Right, theme.ColorMauve),
+		header(i18n.T("tokens"), colTokens, lipgloss.Right, theme.ColorLavender),
+		header(i18n.T("cost"), colCost, lipgloss.Right, theme.ColorSkyBlue),
+	}, " "))
+	rows = append(rows, theme.MutedStyle.Render(strings.Repeat("─",
+		colProject+colStart+colLast+colDur+colModels+colMsgs+colTokens+colCost+colGaps)))
+
+	// 8 = card border top/bottom (2) + title (1) + help line (1)
+	//   + header (1) + separator (1) + scroll indicator (1) + padding (1)
+	visibleRows := contentHeight - 8
+	if visibleRows < 3 {
+		visibleRows = 3
+	}
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	}
+	if v.cursor >= v.scroll+visibleRows {
+		v.scroll = v.cursor - visibleRows + 1
+	}
+
+	for i := v.scroll; i < len(v.sessions) && i < v.scroll+visibleRows; i++ {
+		s := v.sessions[i]
+		hl := i == v.cursor
+		name := s.ProjectName
+		if name == "" {
+			name = "-"
+		}
+		cells := []string{
+			cellStyle(colProject, lipgloss.Left, hl).Foreground(theme.ColorGold).Render(components.TruncateText(name, colProject)),
+			cellStyle(colStart, lipgloss.Left, hl).Foreground(theme.ColorBodyText).Render(s.FirstActivity.In(v.tz).Format("Jan 02 15:04")),
+			cellStyle(colLast, lipgloss.Left, hl).Foreground(theme.ColorBodyText).Render(s.LastActivity.In(v.tz).Format("Jan 02 15:04")),
+			cellStyle(colDur, lipgloss.Right, hl).Foreground(theme.ColorPeach).Render(components.FormatDuration(s.Duration)),
+			cellStyle(colModels, lipgloss.Left, hl).Foreground(theme.ColorBodyText).Render(components.TruncateText(modelMix(s.Models), colModels)),
+			cellStyle(colMsgs, lipgloss.Right, hl).Foreground(theme.ColorMauve).Render(components.FormatNumber(s.MessageCount)),
+			cellStyle(colTokens, lipgloss.Right, hl).Foreground(theme.ColorLavender).Render(components.FormatCompact(s.TotalTokens())),
+			cellStyle(colCost, lipgloss.Right, hl).Foreground(theme.ColorSkyBlue).Render(fmt.Sprintf("$%.2f", s.TotalCost)),
+		}
+		gap := " "
+		if hl {
+			gap = lipgloss.NewStyle().Background(theme.ColorElevatedBg).Render(" ")
+		}
+		rows = append(rows, strings.Join(cells, gap))
+	}
+
+	if len(v.sessions) > visibleRows {
+		rows = append(rows, theme.MutedStyle.Render(
+			fmt.Sprintf("  [%d-%d / %d]", v.scroll+1, min(v.scroll+visibleRows, len(v.sessions)), len(v.sessions))))
+	}
+
+	card.Content = strings.Join(rows, "\n")
+	return card.Render()
+}
+
+func (v *SessionsView) renderDetail(s domain.SessionAggregate, cardWidth int, compact bool) string {
+	name := s.ProjectName
+	if name == "" {
+		name = "-"
+	}
+	summaryCard := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.Tf("session_detail", name, s.SessionID), v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	innerW := summaryCard.InnerWidth()
+
+	statGap := 2
+	statW := (innerW - statGap*4) / 5
+	if statW < 10 {
+		statW = 10
+	}
+	stats := []components.StatCard{
+		{Value: fmt.Sprintf("$%.2f", s.TotalCost), Label: i18n.T("cost"), Width: statW, Color: theme.ColorSkyBlue},
+		{Value: components.FormatCompact(s.TotalTokens()), Label: i18n.T("tokens"), Width: statW, Color: theme.ColorLavender},
+		{Value: components.FormatNumber(s.MessageCount), Label: i18n.T("msgs"), Width: statW, Color: theme.ColorMauve},
+		{Value: components.FormatDuration(s.Duration), Label: i18n.T("duration"), Width: statW, Color: theme.ColorPeach},
+		{Value: components.FormatCompact(s.CacheReadTokens), Label: i18n.T("cache_read"), Width: statW, Color: theme.ColorGold},
+	}
+	summaryCard.Content = components.CenterBlock(components.RenderStatRow(stats, statGap), innerW) + "\n" +
+		components.CenterBlock(theme.MutedStyle.Render(fmt.Sprintf("%s - %s · %s",
+			s.FirstActivity.In(v.tz).Format("Jan 02 15:04"),
+			s.LastActivity.In(v.tz).Format("Jan 02 15:04"),
+			modelMix(s.Models))), innerW)
+
+	entriesCard := components.Card{
+		Title:   theme.GradientText(i18n.T("session_entries"), string(theme.ColorMauve), string(theme.ColorPeach)),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	entriesW := entriesCard.InnerWidth()
+
+	colTime := 15
+	colIn := 8
+	colOut := 8
+	colCache := 8
+	colCost := 9
+	colGaps := 5
+	colModel := entriesW - colTime - colIn - colOut - colCache - colCost - colGaps
+	if colModel < 12 {
+		colModel = 12
+	}
+	cell := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Render(text)
+	}
+	header := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Bold(true).Render(text)
+	}
+
+	var entries []domain.UsageEntry
+	for _, e := range v.entries {
+		if e.SessionID == s.SessionID {
+			entries = append(entries, e)
+		}
+	}
+
+	rows := []string{
+		strings.Join([]string{
+			header(i18n.T("time"), colTime, lipgloss.Left, theme.ColorBrightText),
+			header(i18n.T("model"), colModel, lipgloss.Left, theme.ColorBrightText),
+			header(i18n.T("input_tokens"), colIn, lipgloss.Right, theme.ColorLavender),
+			header(i18n.T("output_tokens"), colOut, lipgloss.Right, theme.ColorMauve),
+			header(i18n.T("cache_read"), colCache, lipgloss.Right, theme.ColorPeach),
+			header(i18n.T("cost"), colCost, lipgloss.Right, theme.ColorSkyBlue),
+		}, " "),
+		theme.MutedStyle.Render(strings.Repeat("─", colTime+colModel+colIn+colOut+colCache+colCost+colGaps)),
+	}
+	for n, i := 0, len(entries)-1; i >= 0; n, i = n+1, i-1 {
+		if n == maxSessionEntries {
+			rows = append(rows, theme.MutedStyle.Render(i18n.Tf("more_entries", i+1)))
+			break
+		}
+		e := entries[i]
+		row := strings.Join([]string{
+			cell(e.Timestamp.In(v.tz).Format("Jan 02 15:04:05"), colTime, lipgloss.Left, theme.ColorMutedText),
+			cell(components.TruncateText(e.Model, colModel), colModel, lipgloss.Left, theme.ColorBodyText),
+			cell(components.FormatCompact(e.InputTokens), colIn, lipgloss.Right, theme.ColorLavender),
+			cell(components.FormatCompact(e.OutputTokens), colOut, lipgloss.Right, theme.ColorMauve),
+			cell(components.FormatCompact(e.CacheReadTokens), colCache, lipgloss.Right, theme.ColorPeach),
+			cell(fmt.Sprintf("$%.4f", e.CostUSD), colCost, lipgloss.Right, theme.ColorSkyBlue),
+		}, " ")
+		rows = append(rows, components.RowBackground(n).Render(row))
+	}
+	if len(entries) == 0 {
+		rows = append(rows, theme.MutedStyle.Render(i18n.T("no_data")))
+	}
+	entriesCard.Content = strings.Join(rows, "\n")
+
+	footer := components.HelpFooter(i18n.T("detail_back_help"))
+	return summaryCard.Render() + "\n" + entriesCard.Render() + "\n" + footer
+}
@@ -0,0 +1,181 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// ToolScope selects which entries the tools view aggregates.
+type ToolScope int
+
+const (
+	ToolScopeAll     ToolScope = iota // every entry in the current filter
+	ToolScopeToday                    // today in the configured timezone
+	ToolScopeSession                  // the most recently active session
+	toolScopeCount
+)
+
+// ToolsView lists tool invocations with the tokens and cost of the turns
+// that made them.
+type ToolsView struct {
+	entries  []domain.UsageEntry
+	tz       *time.Location
+	scope    ToolScope
+	AnimTick uint
+}
+
+func NewToolsView(tz *time.Location) *ToolsView {
+	return &ToolsView{tz: tz}
+}
+
+func (v *ToolsView) SetData(entries []domain.UsageEntry) {
+	v.entries = entries
+}
+
+func (v *ToolsView) Update(msg tea.Msg) tea.Cmd {
+	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.String() {
+		case "v":
+			v.scope = (v.scope + 1) % toolScopeCount
+			return KeyHandledCmd
+		}
+	}
+	return nil
+}
+
+// group aggregates the entries in the current scope into one group and
+// also returns the number of turns in scope, with or without tool calls.
+func (v *ToolsView) group() (domain.ToolGroup, int, string) {
+	var key domain.KeyFunc
+	var want, label string
+	switch v.scope {
+	case ToolScopeToday:
+		key = domain.KeyDay(v.tz)
+		want = time.Now().In(v.tz).Format("2006-01-02")
+		label = i18n.T("today")
+	case ToolScopeSession:
+		key = domain.KeySession
+		var last time.Time
+		for _, e := range v.entries {
+			if e.SessionID != "" && e.Timestamp.After(last) {
+				last, want = e.Timestamp, e.SessionID
+			}
+		}
+		label = i18n.T("latest_session")
+	default:
+		key = domain.KeyAll
+		want = "all"
+		label = i18n.T("all_entries")
+	}
+
+	acc := domain.NewToolAccumulator(key)
+	turns := 0
+	for _, e := range v.entries {
+		if key(e) == want {
+			acc.Add(e)
+			turns++
+		}
+	}
+	if groups := acc.Groups(); len(groups) > 0 {
+		return groups[0], turns, label
+	}
+	return domain.ToolGroup{}, turns, label
+}
+
+func (v *ToolsView) Render(width, height int, compact bool) string {
+	cardWidth := width - 4
+	g, turns, label := v.group()
+
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(fmt.Sprintf("%s · %s", i18n.T("tool_usage"), label), v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	var sections []string
+	sections = append(sections, lipgloss.PlaceHorizontal(innerW, lipgloss.Right,
+		theme.MutedStyle.Render(i18n.T("tools_help"))))
+
+	share := 0.0
+	if turns > 0 {
+		share = float64(g.Turns) / float64(turns) * 100
+	}
+
+	statGap := 2
+	statW := (innerW - statGap*3) / 4
+	if statW < 10 {
+		statW = 10
+	}
+	stats := []components.StatCard{
+		{Value: components.FormatCompact(g.Calls), Label: i18n.T("tool_calls"), Width: statW, Color: theme.ColorSkyBlue},
+		{Value: components.FormatCompact(g.Turns), Label: i18n.T("tool_turns"), Width: statW, Color: theme.ColorLavender},
+		{Value: fmt.Sprintf("%d", len(g.Tools)), Label: i18n.T("distinct_tools"), Width: statW, Color: theme.ColorMauve},
+		{Value: fmt.Sprintf("%.0f%%", share), Label: i18n.T("turns_with_tools"), Width: statW, Color: theme.ColorPeach},
+	}
+	sections = append(sections, components.CenterBlock(components.RenderStatRow(stats, statGap), innerW))
+	sections = append(sections, "")
+	sections = append(sections, renderToolTable(g.Tools, innerW, 0))
+
+	card.Content = strings.Join(sections, "\n")
+	return card.Render()
+}
+
+// renderToolTable renders tool usage as a table of calls, turns, tokens
+// and cost. limit > 0 caps the number of rows.
+func renderToolTable(tools []domain.ToolUsage, innerW, limit int) string {
+	colName := 24
+	colCalls := 8
+	colTurns := 8
+	colTokens := 10
+	colCost := 10
+	colGaps := 4
+	if remaining := innerW - colName - colCalls - colTurns - colTokens - colCost - colGaps; remaining > 0 {
+		colName += remaining
+	}
+
+	cell := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Render(text)
+	}
+	header := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Bold(true).Render(text)
+	}
+
+	rows := []string{
+		strings.Join([]string{
+			header(i18n.T("tool"), colName, lipgloss.Left, theme.ColorBrightText),
+			header(i18n.T("calls"), colCalls, lipgloss.Right, theme.ColorSkyBlue),
+			header(i18n.T("turns"), colTurns, lipgloss.Right, theme.ColorLavender),
+			header(i18n.T("tokens"), colTokens, lipgloss.Right, theme.ColorMauve),
+			header(i18n.T("cost"), colCost, lipgloss.Right, theme.ColorGold),
+		}, " "),
+		theme.MutedStyle.Render(strings.Repeat("─", colName+colCalls+colTurns+colTokens+colCost+colGaps)),
+	}
+
+	for i, tu := range tools {
+		if limit > 0 && i == limit {
+			rows = append(rows, theme.MutedStyle.Render(i18n.Tf("more_tools", len(tools)-limit)))
+			break
+		}
+		row := strings.Join([]string{
+			cell(tu.Name, colName, lipgloss.Left, theme.ColorBrightText),
+			cell(components.FormatNumber(tu.Calls), colCalls, lipgloss.Right, theme.ColorSkyBlue),
+			cell(components.FormatNumber(tu.Turns), colTurns, lipgloss.Right, theme.ColorLavender),
+			cell(components.FormatCompact(tu.Tokens), colTokens, lipgloss.Right, theme.ColorMauve),
+			cell(fmt.Sprintf("$%.2f", tu.Cost), colCost, lipgloss.Right, theme.ColorGold),
+		}, " ")
+		rows = append(rows, components.RowBackground(i).Render(row))
+	}
+	if len(tools) == 0 {
+		rows = append(rows, theme.MutedStyle.Render(i18n.T("no_tool_calls")))
+	}
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,166 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/anomredux/claude-smi/internal/domain"
+	"github.com/anomredux/claude-smi/internal/i18n"
+	"github.com/anomredux/claude-smi/internal/theme"
+	"github.com/anomredux/claude-smi/internal/ui/components"
+)
+
+// WeeklyView lists the 7-day rate-limit windows, newest first, with the
+// current window summarised on top.
+type WeeklyView struct {
+	windows  []domain.WeeklyWindow
+	tz       *time.Location
+	AnimTick uint
+}
+
+func NewWeeklyView(tz *time.Location) *WeeklyView {
+	return &WeeklyView{tz: tz}
+}
+
+// SetData takes the weekly windows, oldest first.
+func (v *WeeklyView) SetData(windows []domain.WeeklyWindow) {
+	v.windows = windows
+}
+
+func (v *WeeklyView) Update(msg tea.Msg) tea.Cmd {
+	return nil
+}
+
+func (v *WeeklyView) Render(width, height int, compact bool) string {
+	cardWidth := width - 4
+	card := components.Card{
+		Title:   theme.AnimatedGradientText(i18n.T("weekly_windows"), v.AnimTick),
+		Width:   cardWidth,
+		Compact: compact,
+	}
+	innerW := card.InnerWidth()
+
+	if len(v.windows) == 0 {
+		card.Content = theme.MutedStyle.Render(i18n.T("no_weekly_windows"))
+		return card.Render()
+	}
+
+	var sections []string
+
+	// Summary of the latest window
+	cur := v.windows[len(v.windows)-1]
+	peak := "-"
+	if cur.HasUtilization {
+		peak = fmt.Sprintf("%.0f%%", cur.PeakUtilization)
+	}
+	statGap := 2
+	statW := (innerW - statGap*3) / 4
+	if statW < 10 {
+		statW = 10
+	}
+	stats := []components.StatCard{
+		{Value: fmt.Sprintf("$%.2f", cur.TotalCost), Label: i18n.T("cost"), Width: statW, Color: theme.ColorSkyBlue},
+		{Value: components.FormatCompact(cur.TotalTokens), Label: i18n.T("tokens"), Width: statW, Color: theme.ColorLavender},
+		{Value: components.FormatNumber(cur.MessageCount), Label: i18n.T("msgs"), Width: statW, Color: theme.ColorMauve},
+		{Value: peak, Label: i18n.T("peak_utilization"), Width: statW, Color: theme.ColorPeach},
+	}
+	sections = append(sections, components.CenterBlock(components.RenderStatRow(stats, statGap), innerW))
+	sections = append(sections, "")
+
+	colWeek := 23
+	colTokens := 8
+	colCost := 9
+	colMsgs := 7
+	colPeak := 6
+	colGaps := 5
+	colModels := innerW - colWeek - colTokens - colCost - colMsgs - colPeak - colGaps
+	if colModels < 12 {
+		colModels = 12
+	}
+
+	cell := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Render(text)
+	}
+	header := func(text string, width int, align lipgloss.Position, color lipgloss.Color) string {
+		return lipgloss.NewStyle().Width(width).Align(align).Foreground(color).Bold(true).Render(text)
+	}
+
+	sections = append(sections, strings.Join([]string{
+		header(i18n.T("week"), colWeek, lipgloss.Left, theme.ColorBrightText),
+		header(i18n.T("tokens"), colTokens, lipgloss.Right, theme.ColorLavender),
+		header(i18n.T("cost"), colCost, lipgloss.Right, theme.ColorSkyBlue),
+		header(i18n.T("msgs"), colMsgs, lipgloss.Right, theme.ColorMauve),
+		header(i18n.T("peak"), colPeak, lipgloss.Right, theme.ColorPeach),
+		header(i18n.T("model_mix"), colModels, lipgloss.Left, theme.ColorBrightText),
+	}, " "))
+	sections = append(sections, theme.MutedStyle.Render(strings.Repeat("─", colWeek+colTokens+colCost+colMsgs+colPeak+colModels+colGaps)))
+
+	for i := len(v.windows) - 1; i >= 0; i-- {
+		w := v.windows[i]
+		week := fmt.Sprintf("%s - %s",
+			w.StartTime.In(v.tz).Format("Jan 02 15:04"),
+			w.EndTime.Add(-time.Minute).In(v.tz).Format("Jan 02"))
+		weekColor := theme.ColorBodyText
+		if w.Status == domain.BlockActive {
+			weekColor = theme.ColorGold
+		}
+		peak := "-"
+		if w.HasUtilization {
+			peak = fmt.Sprintf("%.0f%%", w.PeakUtilization)
+		}
+		row := strings.Join([]string{
+			cell(week, colWeek, lipgloss.Left, weekColor),
+			cell(components.FormatCompact(w.TotalTokens), colTokens, lipgloss.Right, theme.ColorLavender),
+			cell(fmt.Sprintf("$%.2f", w.TotalCost), colCost, lipgloss.Right, theme.ColorSkyBlue),
+			cell(components.FormatNumber(w.MessageCount), colMsgs, lipgloss.Right, theme.ColorMauve),
+			cell(peak, colPeak, lipgloss.Right, theme.ColorPeach),
+			cell(components.TruncateText(modelMix(w.Models), colModels), colModels, lipgloss.Left, theme.ColorBodyText),
+		}, " ")
+		sections = append(sections, components.RowBackground(len(v.windows)-1-i).Render(row))
+	}
+
+	card.Content = strings.Join(sections, "\n")
+	return card.Render()
+}
+
+// modelMix summarises token shares by model family, largest first, e.g.
+// "opus 80% · sonnet 20%".
+func modelMix(models map[string]domain.ModelBreakdown) string {
+	shares := make(map[string]float64)
+	for _, mb := range models {
+		shares[modelFamily(mb.Model)] += mb.Percentage
+	}
+	families := make([]string, 0, len(shares))
+	for f := range shares {
+		families = append(families, f)
+	}
+	sort.Slice(families, func(i, j int) bool {
+		if shares[families[i]] != shares[families[j]] {
+			return shares[families[i]] > shares[families[j]]
+		}
+		return families[i] < families[j]
+	})
+	parts := make([]string, 0, len(families))
+	for _, f := range families {
+		parts = append(parts, fmt.Sprintf("%s %.0f%%", f, shares[f]))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// modelFamily returns the family of a model id: "claude-opus-4-6" and
+// "claude-3-5-sonnet-20241022" give "opus" and "sonnet".
+func modelFamily(model string) string {
+	for _, part := range strings.Split(model, "-") {
+		if part == "" || part == "claude" {
+			continue
+		}
+		if strings.Trim(part, "abcdefghijklmnopqrstuvwxyz") == "" {
+			return part
+		}
+	}
+	return model
+}